@@ -1,8 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/pagination"
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/prompts"
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/resources"
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/tools/instancetype"
@@ -14,6 +16,17 @@ import (
 )
 
 func main() {
+	maxResponseBytes := flag.Int("max-response-bytes", pagination.DefaultMaxResponseBytes,
+		"Maximum size in bytes of a single list response before items are truncated, 0 disables the budget")
+	maxResponseTokens := flag.Int("max-response-tokens", 0,
+		fmt.Sprintf("Maximum size in approximate tokens of a single list response, overrides --max-response-bytes assuming %d bytes per token", pagination.BytesPerToken))
+	flag.Parse()
+
+	if *maxResponseTokens > 0 {
+		*maxResponseBytes = *maxResponseTokens * pagination.BytesPerToken
+	}
+	pagination.SetMaxResponseBytes(*maxResponseBytes)
+
 	// Create MCP server
 	s := server.NewMCPServer(
 		"kubevirt MCP server demo 🚀",
@@ -31,15 +44,26 @@ func main() {
 
 	// TODO tools
 	// list instance types
-	// change instance type
 	s.AddTool(
 		mcp.NewTool(
 			"list_vms",
-			mcp.WithDescription("list the names of virtual machine within a given namespace"),
+			mcp.WithDescription("list the names of virtual machine within a given namespace, or an inventory grouped by namespace when all_namespaces or namespaces is given"),
 			mcp.WithString(
 				"namespace",
-				mcp.Description("The namespace of the virtual machine"),
-				mcp.Required()),
+				mcp.Description("The namespace of the virtual machine, required unless all_namespaces or namespaces is given")),
+			mcp.WithBoolean(
+				"all_namespaces",
+				mcp.Description("Optionally list virtual machines across all namespaces")),
+			mcp.WithArray(
+				"namespaces",
+				mcp.Description("Optional list of namespaces to list virtual machines from"),
+				mcp.WithStringItems()),
+			mcp.WithNumber(
+				"limit",
+				mcp.Description("Optional maximum number of virtual machines to return (default 100, max 500)")),
+			mcp.WithString(
+				"cursor",
+				mcp.Description("Optional cursor returned by a previous call to fetch the next page")),
 		),
 		vm.List,
 	)
@@ -79,7 +103,7 @@ func main() {
 	s.AddTool(
 		mcp.NewTool(
 			"restart_vm",
-			mcp.WithDescription("restart the virtual machine with a given name in the provided namespace"),
+			mcp.WithDescription("restart the virtual machine with a given name in the provided namespace through the KubeVirt restart subresource, starting it when it is not running"),
 			mcp.WithString(
 				"namespace",
 				mcp.Description("The namespace of the virtual machine"),
@@ -88,14 +112,58 @@ func main() {
 				"name",
 				mcp.Description("The name of the virtual machine"),
 				mcp.Required()),
+			mcp.WithBoolean(
+				"force",
+				mcp.Description("Restart immediately without waiting for the guest to shut down (default false)")),
+			mcp.WithNumber(
+				"grace_period",
+				mcp.Description("Optional grace period in seconds, only 0 is supported and only together with force")),
 		),
 		vm.Restart,
 	)
 
+	s.AddTool(
+		mcp.NewTool(
+			"soft_reboot_vm",
+			mcp.WithDescription("reboot the guest of a running virtual machine through the guest agent, or an ACPI reboot request when no agent is connected, without recreating the virtual machine instance"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+		),
+		vm.SoftReboot,
+	)
+
+	s.AddTool(
+		mcp.NewTool(
+			"force_stop_vm",
+			mcp.WithDescription("stop the virtual machine with a given name in the provided namespace immediately with a grace period of 0, without waiting for the guest to shut down"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+		),
+		vm.ForceStop,
+	)
+
 	s.AddTool(
 		mcp.NewTool(
 			"list_instancetypes",
 			mcp.WithDescription("list the name of all instance types"),
+			mcp.WithNumber(
+				"limit",
+				mcp.Description("Optional maximum number of instance types to return (default 100, max 500)")),
+			mcp.WithString(
+				"cursor",
+				mcp.Description("Optional cursor returned by a previous call to fetch the next page")),
 		),
 		instancetype.List,
 	)
@@ -108,6 +176,13 @@ func main() {
 				"name",
 				mcp.Description("The name of the instance type"),
 				mcp.Required()),
+			mcp.WithString(
+				"format",
+				mcp.Description("Optional output format: json (default), yaml or summary"),
+				mcp.Enum("json", "yaml", "summary")),
+			mcp.WithBoolean(
+				"full",
+				mcp.Description("Optionally keep managed fields, last-applied annotations and empty values in the output")),
 		),
 		instancetype.Get,
 	)
@@ -120,6 +195,13 @@ func main() {
 				"name",
 				mcp.Description("The name of the preference"),
 				mcp.Required()),
+			mcp.WithString(
+				"format",
+				mcp.Description("Optional output format: json (default), yaml or summary"),
+				mcp.Enum("json", "yaml", "summary")),
+			mcp.WithBoolean(
+				"full",
+				mcp.Description("Optionally keep managed fields, last-applied annotations and empty values in the output")),
 		),
 		preference.Get,
 	)
@@ -152,6 +234,10 @@ func main() {
 				"name",
 				mcp.Description("The name of the virtual machine"),
 				mcp.Required()),
+			mcp.WithString(
+				"format",
+				mcp.Description("Optional output format: json (default) or yaml"),
+				mcp.Enum("json", "yaml")),
 		),
 		vm.GetStatus,
 	)
@@ -168,6 +254,10 @@ func main() {
 				"name",
 				mcp.Description("The name of the virtual machine"),
 				mcp.Required()),
+			mcp.WithString(
+				"format",
+				mcp.Description("Optional output format: json (default) or yaml"),
+				mcp.Enum("json", "yaml")),
 		),
 		vm.GetConditions,
 	)
@@ -184,6 +274,10 @@ func main() {
 				"name",
 				mcp.Description("The name of the virtual machine"),
 				mcp.Required()),
+			mcp.WithString(
+				"format",
+				mcp.Description("Optional output format: json (default) or yaml"),
+				mcp.Enum("json", "yaml")),
 		),
 		vm.GetPhase,
 	)
@@ -191,7 +285,7 @@ func main() {
 	s.AddTool(
 		mcp.NewTool(
 			"patch_vm",
-			mcp.WithDescription("apply a JSON merge patch to modify a virtual machine configuration"),
+			mcp.WithDescription("apply a JSON merge patch to modify a virtual machine configuration, consider create_vm_snapshot first so risky changes can be rolled back"),
 			mcp.WithString(
 				"namespace",
 				mcp.Description("The namespace of the virtual machine"),
@@ -211,7 +305,7 @@ func main() {
 	s.AddTool(
 		mcp.NewTool(
 			"create_vm",
-			mcp.WithDescription("create a virtual machine with the given name, container disk image (supports OS names like 'fedora', 'ubuntu'), and optional instancetype and preference"),
+			mcp.WithDescription("create a virtual machine with the given name, container disk image (supports OS names like 'fedora', 'ubuntu'), optional instancetype and preference given by name or inferred from the boot volume, and optional cloud-init user data, network data and SSH keys"),
 			mcp.WithString(
 				"namespace",
 				mcp.Description("The namespace for the virtual machine"),
@@ -222,18 +316,105 @@ func main() {
 				mcp.Required()),
 			mcp.WithString(
 				"container_disk",
-				mcp.Description("The container disk image to use for the VM (supports OS names like 'fedora', 'ubuntu' or full URLs)"),
-				mcp.Required()),
+				mcp.Description("The container disk image to use for the VM (supports OS names like 'fedora', 'ubuntu' or full URLs), required unless data_source or disk_image_url is given")),
+			mcp.WithString(
+				"data_source",
+				mcp.Description("Optional CDI DataSource to clone a persistent root disk from, as name or namespace/name, e.g. openshift-virtualization-os-images/fedora; its default instancetype and preference labels are used when none are given")),
+			mcp.WithString(
+				"disk_image_url",
+				mcp.Description("Optional docker:// registry or http(s):// URL to import a persistent root disk from")),
+			mcp.WithString(
+				"disk_size",
+				mcp.Description("Size of the persistent root disk, e.g. 30Gi, required with disk_image_url and defaulting to the source size with data_source")),
+			mcp.WithString(
+				"storage_class",
+				mcp.Description("Optional storage class of the persistent root disk")),
+			mcp.WithString(
+				"access_mode",
+				mcp.Description("Optional access mode of the persistent root disk, defaults to the storage profile"),
+				mcp.Enum("ReadWriteOnce", "ReadWriteMany", "ReadOnlyMany", "ReadWriteOncePod")),
+			mcp.WithString(
+				"volume_mode",
+				mcp.Description("Optional volume mode of the persistent root disk, defaults to the storage profile"),
+				mcp.Enum("Filesystem", "Block")),
 			mcp.WithString(
 				"instancetype",
-				mcp.Description("Optional instance type name")),
+				mcp.Description("Optional instance type name, a VirtualMachineInstancetype in the VM namespace is used when one exists, otherwise a VirtualMachineClusterInstancetype")),
 			mcp.WithString(
 				"preference",
-				mcp.Description("Optional preference name")),
+				mcp.Description("Optional preference name, a VirtualMachinePreference in the VM namespace is used when one exists, otherwise a VirtualMachineClusterPreference")),
+			mcp.WithString(
+				"architecture",
+				mcp.Description("Optional guest architecture, checked against the schedulable nodes and the container disk image, whose arch specific manifest or tag is used"),
+				mcp.Enum("amd64", "arm64", "s390x")),
+			mcp.WithBoolean(
+				"infer_instancetype",
+				mcp.Description("Infer the instance type from the instancetype.kubevirt.io/default-instancetype label of the boot volume, read by KubeVirt for data_source and from the image config for container disks and docker:// URLs")),
+			mcp.WithBoolean(
+				"infer_preference",
+				mcp.Description("Infer the preference from the instancetype.kubevirt.io/default-preference label of the boot volume, read by KubeVirt for data_source and from the image config for container disks and docker:// URLs")),
+			mcp.WithString(
+				"user_data",
+				mcp.Description("Optional cloud-init user data, a #cloud-config YAML document or a #! script, validated before submission")),
+			mcp.WithString(
+				"network_data",
+				mcp.Description("Optional cloud-init network config YAML, e.g. \"version: 2\" followed by ethernets")),
+			mcp.WithArray(
+				"ssh_authorized_keys",
+				mcp.Description("Optional SSH public keys stored in a secret owned by the VM and injected through accessCredentials"),
+				mcp.WithStringItems()),
+			mcp.WithString(
+				"cloud_init_secret",
+				mcp.Description("Optional name of a secret holding the user data under the userdata key, instead of user_data and network_data")),
+			mcp.WithString(
+				"pod_network_binding",
+				mcp.Description("Optional binding of the pod network interface, defaults to masquerade, none leaves only the secondary networks"),
+				mcp.Enum("masquerade", "bridge", "none")),
+			mcp.WithArray(
+				"networks",
+				mcp.Description("Optional Multus NetworkAttachmentDefinitions, as name or namespace/name, attached as bridge interfaces net1, net2, ..."),
+				mcp.WithStringItems()),
+			mcp.WithObject(
+				"mac_addresses",
+				mcp.Description("Optional static MAC addresses keyed by interface name, default for the pod network and net1, net2, ... for the secondary networks")),
+			mcp.WithString(
+				"interface_model",
+				mcp.Description("Optional model of every network interface, e.g. virtio or e1000e")),
+			mcp.WithArray(
+				"expose_ports",
+				mcp.Description("Optional TCP ports of the pod network exposed by a service named after the VM, e.g. [22] or [3389]"),
+				mcp.WithNumberItems()),
+			mcp.WithString(
+				"service_type",
+				mcp.Description("Optional type of the service created for expose_ports, defaults to ClusterIP"),
+				mcp.Enum("ClusterIP", "NodePort", "LoadBalancer")),
+			mcp.WithString(
+				"cloud_init_datasource",
+				mcp.Description("Optional cloud-init datasource, defaults to nocloud"),
+				mcp.Enum("nocloud", "configdrive")),
 		),
 		vm.Create,
 	)
 
+	s.AddTool(
+		mcp.NewTool(
+			"create_vm_from_manifest",
+			mcp.WithDescription("create a virtual machine from a full VirtualMachine YAML or JSON manifest, validating it strictly against the KubeVirt API types and with a server side dry-run first, reporting validation errors field by field"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace for the virtual machine, the manifest namespace must match it when set"),
+				mcp.Required()),
+			mcp.WithString(
+				"manifest",
+				mcp.Description("The VirtualMachine manifest as YAML or JSON"),
+				mcp.Required()),
+			mcp.WithBoolean(
+				"dry_run",
+				mcp.Description("Only validate the manifest without creating the virtual machine (default false)")),
+		),
+		vm.CreateFromManifest,
+	)
+
 	s.AddTool(
 		mcp.NewTool(
 			"delete_vm",
@@ -253,7 +434,7 @@ func main() {
 	s.AddTool(
 		mcp.NewTool(
 			"pause_vm",
-			mcp.WithDescription("pause the virtual machine with a given name in the provided namespace"),
+			mcp.WithDescription("pause the running virtual machine with a given name in the provided namespace, leaving its run strategy unchanged"),
 			mcp.WithString(
 				"namespace",
 				mcp.Description("The namespace of the virtual machine"),
@@ -269,7 +450,7 @@ func main() {
 	s.AddTool(
 		mcp.NewTool(
 			"unpause_vm",
-			mcp.WithDescription("unpause the virtual machine with a given name in the provided namespace"),
+			mcp.WithDescription("unpause the paused virtual machine with a given name in the provided namespace"),
 			mcp.WithString(
 				"namespace",
 				mcp.Description("The namespace of the virtual machine"),
@@ -282,10 +463,85 @@ func main() {
 		vm.Unpause,
 	)
 
+	s.AddTool(
+		mcp.NewTool(
+			"bulk_vm_action",
+			mcp.WithDescription("start, stop, restart, pause, unpause or delete every virtual machine matching a label selector or name list, returning a per VM result of success, skipped (already in the requested state) or error; more than 10 virtual machines require confirm"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machines"),
+				mcp.Required()),
+			mcp.WithString(
+				"action",
+				mcp.Description("The action to apply to every virtual machine"),
+				mcp.Enum("start", "stop", "restart", "pause", "unpause", "delete"),
+				mcp.Required()),
+			mcp.WithString(
+				"label_selector",
+				mcp.Description("Label selector of the virtual machines, e.g. env=dev, mutually exclusive with names")),
+			mcp.WithArray(
+				"names",
+				mcp.Description("Names of the virtual machines, mutually exclusive with label_selector"),
+				mcp.WithStringItems()),
+			mcp.WithNumber(
+				"concurrency",
+				mcp.Description("Number of virtual machines acted on in parallel, between 1 and 20, defaults to 5")),
+			mcp.WithBoolean(
+				"confirm",
+				mcp.Description("Set to true to act on more than 10 virtual machines")),
+		),
+		vm.BulkAction,
+	)
+
+	s.AddTool(
+		mcp.NewTool(
+			"get_vm_serial_output",
+			mcp.WithDescription("capture the serial console output of a running virtual machine for a bounded time or number of bytes of text, counted after terminal escape sequences are stripped, falling back to the guest-console-log container of the virt-launcher pod when the console yields nothing or cannot be opened"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithNumber(
+				"duration_seconds",
+				mcp.Description("How long to capture the live console, between 1 and 60 seconds, defaults to 5")),
+			mcp.WithNumber(
+				"max_bytes",
+				mcp.Description("Maximum bytes of text to return, counted after ANSI escape sequences and control characters are stripped, up to 1048576, defaults to 16384")),
+			mcp.WithBoolean(
+				"send_newline",
+				mcp.Description("Send a newline after connecting so the guest prints its login prompt again")),
+			mcp.WithString(
+				"source",
+				mcp.Description("Where to read the output from, auto tries the live console first and falls back to the console log"),
+				mcp.Enum("auto", "console", "log")),
+		),
+		vm.SerialOutput,
+	)
+
+	s.AddTool(
+		mcp.NewTool(
+			"get_vm_network",
+			mcp.WithDescription("get the network view of a virtual machine, joining its interfaces and networks with the IPs, MACs, guest interface names, info source and link state reported by the running instance, the backing NetworkAttachmentDefinitions and the services selecting its virt-launcher pod"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+		),
+		vm.Network,
+	)
+
 	s.AddTool(
 		mcp.NewTool(
 			"get_vm_disks",
-			mcp.WithDescription("get the list of disks for a specified virtual machine in the given namespace"),
+			mcp.WithDescription("get the disks of a virtual machine with their device type, bus, boot order and backing volume source, the capacity, storage class and modes of their persistent volume claims, data volume phase and progress, and the runtime volume status of the running instance"),
 			mcp.WithString(
 				"namespace",
 				mcp.Description("The namespace of the virtual machine"),
@@ -298,177 +554,423 @@ func main() {
 		vm.Disks,
 	)
 
-	// Add MCP Resource Templates
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/vms",
-			"Virtual Machines",
-			mcp.WithTemplateDescription("List of virtual machines in a namespace"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"migrate_vm",
+			mcp.WithDescription("live migrate the running virtual machine with a given name in the provided namespace to another node"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithObject(
+				"added_node_selector",
+				mcp.Description("Optional node labels the migration target must additionally match, e.g. {\"kubernetes.io/hostname\": \"node02\"}")),
 		),
-		resources.VmsList,
+		vm.Migrate,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/vm/{name}",
-			"Virtual Machine",
-			mcp.WithTemplateDescription("Individual virtual machine details"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"get_migration_status",
+			mcp.WithDescription("get the phase, source and target nodes and failure reason of the latest migration of a virtual machine, the data transferred is unavailable as KubeVirt only reports it through metrics"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
 		),
-		resources.VmGet,
+		vm.GetMigrationStatus,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/vmis",
-			"Virtual Machine Instances",
-			mcp.WithTemplateDescription("List of virtual machine instances in a namespace"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"cancel_migration",
+			mcp.WithDescription("cancel the in-flight migration of a virtual machine by deleting its migration object"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
 		),
-		resources.VmisList,
+		vm.CancelMigration,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/vmi/{name}",
-			"Virtual Machine Instance",
-			mcp.WithTemplateDescription("Individual virtual machine instance details"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"add_volume_vm",
+			mcp.WithDescription("hotplug an existing PVC or DataVolume into the virtual machine with a given name in the provided namespace"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"volume_name",
+				mcp.Description("The name of the PVC or DataVolume to hotplug"),
+				mcp.Required()),
+			mcp.WithString(
+				"source",
+				mcp.Description("Optional type of the volume, defaults to pvc"),
+				mcp.Enum("pvc", "datavolume")),
+			mcp.WithString(
+				"disk_name",
+				mcp.Description("Optional name of the disk within the virtual machine, defaults to volume_name")),
+			mcp.WithString(
+				"disk_type",
+				mcp.Description("Optional type of the disk, defaults to disk"),
+				mcp.Enum("disk", "lun")),
+			mcp.WithString(
+				"bus",
+				mcp.Description("Optional bus of the disk, defaults to scsi"),
+				mcp.Enum("scsi", "virtio")),
+			mcp.WithBoolean(
+				"persist",
+				mcp.Description("Optionally persist the volume to the virtual machine spec so it survives restarts, required when the virtual machine is stopped")),
 		),
-		resources.VmiGet,
+		vm.AddVolume,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/datavolumes",
-			"Data Volumes",
-			mcp.WithTemplateDescription("List of data volumes with source and storage information"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"remove_volume_vm",
+			mcp.WithDescription("hot-unplug a volume from the virtual machine with a given name in the provided namespace"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"volume_name",
+				mcp.Description("The name of the hotplugged volume to remove"),
+				mcp.Required()),
+			mcp.WithBoolean(
+				"persist",
+				mcp.Description("Optionally also remove the volume from the virtual machine spec")),
 		),
-		resources.DataVolumesList,
+		vm.RemoveVolume,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/datavolume/{name}",
-			"Data Volume",
-			mcp.WithTemplateDescription("Individual data volume specification"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"create_vm_snapshot",
+			mcp.WithDescription("snapshot the virtual machine with a given name in the provided namespace, take one before applying risky changes"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"snapshot_name",
+				mcp.Description("Optional name of the snapshot, generated from the virtual machine name by default")),
+			mcp.WithBoolean(
+				"freeze",
+				mcp.Description("Optionally require a connected guest agent to freeze the filesystems of a running virtual machine, refusing a crash consistent snapshot (default false)")),
+			mcp.WithBoolean(
+				"wait",
+				mcp.Description("Optionally wait for the snapshot to become ready to use")),
 		),
-		resources.DataVolumeGet,
+		vm.CreateSnapshot,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/vm/{name}/status",
-			"VM Status",
-			mcp.WithTemplateDescription("Virtual machine status and phase information"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"list_vm_snapshots",
+			mcp.WithDescription("list the snapshots in the provided namespace, oldest first within each page, optionally only those of a given virtual machine"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("Optional name of the virtual machine to list snapshots of")),
+			mcp.WithNumber(
+				"limit",
+				mcp.Description("Optional maximum number of snapshots to fetch (default 100, max 500)")),
+			mcp.WithString(
+				"cursor",
+				mcp.Description("Optional cursor returned by a previous call to fetch the next page")),
 		),
-		resources.VmGetStatus,
+		vm.ListSnapshots,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/vmi/{name}/guestosinfo",
-			"VMI Guest OS Info",
-			mcp.WithTemplateDescription("Virtual machine instance guest operating system information"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"get_vm_snapshot",
+			mcp.WithDescription("get the readiness, indications, conditions and volume snapshot statuses of a virtual machine snapshot"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the snapshot"),
+				mcp.Required()),
+			mcp.WithString(
+				"snapshot_name",
+				mcp.Description("The name of the snapshot"),
+				mcp.Required()),
 		),
-		resources.VmiGetGuestOSInfo,
+		vm.GetSnapshot,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/vmi/{name}/filesystems",
-			"VMI Filesystems",
-			mcp.WithTemplateDescription("Virtual machine instance filesystem information"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"restore_vm_snapshot",
+			mcp.WithDescription("restore a stopped virtual machine from one of its snapshots"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"snapshot_name",
+				mcp.Description("The name of the snapshot"),
+				mcp.Required()),
+			mcp.WithBoolean(
+				"wait",
+				mcp.Description("Wait for the restore to complete (default true)")),
 		),
-		resources.VmiGetFilesystems,
+		vm.RestoreSnapshot,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/vmi/{name}/userlist",
-			"VMI User List",
-			mcp.WithTemplateDescription("Virtual machine instance user list information"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"delete_vm_snapshot",
+			mcp.WithDescription("delete a virtual machine snapshot"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the snapshot"),
+				mcp.Required()),
+			mcp.WithString(
+				"snapshot_name",
+				mcp.Description("The name of the snapshot"),
+				mcp.Required()),
 		),
-		resources.VmiGetUserList,
+		vm.DeleteSnapshot,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/vm/{name}/console",
-			"VM Console",
-			mcp.WithTemplateDescription("Virtual machine console connection details"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"clone_vm",
+			mcp.WithDescription("clone a virtual machine or virtual machine snapshot into a new virtual machine in the provided namespace, waiting for the clone to succeed"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the source and the new virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine to clone, required unless source_snapshot is given")),
+			mcp.WithString(
+				"source_snapshot",
+				mcp.Description("The name of the virtual machine snapshot to clone instead of a virtual machine")),
+			mcp.WithString(
+				"target_name",
+				mcp.Description("Optional name of the new virtual machine, generated when omitted")),
+			mcp.WithArray(
+				"label_filters",
+				mcp.Description("Optional label filters selecting which virtual machine labels are copied, e.g. [\"*\", \"!example.com/*\"]"),
+				mcp.WithStringItems()),
+			mcp.WithArray(
+				"annotation_filters",
+				mcp.Description("Optional annotation filters selecting which virtual machine annotations are copied"),
+				mcp.WithStringItems()),
+			mcp.WithArray(
+				"template_label_filters",
+				mcp.Description("Optional label filters applied to the virtual machine template"),
+				mcp.WithStringItems()),
+			mcp.WithArray(
+				"template_annotation_filters",
+				mcp.Description("Optional annotation filters applied to the virtual machine template"),
+				mcp.WithStringItems()),
+			mcp.WithObject(
+				"new_mac_addresses",
+				mcp.Description("Optional MAC addresses keyed by interface name, interfaces not listed get a newly generated MAC address")),
+			mcp.WithString(
+				"new_smbios_serial",
+				mcp.Description("Optional SMBIOS serial of the new virtual machine, a new serial is generated when omitted")),
+			mcp.WithBoolean(
+				"wait",
+				mcp.Description("Wait for the clone to succeed and return the new virtual machine (default true)")),
 		),
-		resources.VmGetConsole,
+		vm.Clone,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/instancetypes",
-			"Instance Types",
-			mcp.WithTemplateDescription("List of instance types in a namespace"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"export_vm",
+			mcp.WithDescription("export the disks of a stopped virtual machine, virtual machine snapshot or PVC, returning download links per volume and format and the exported manifest"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the object to export"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine, snapshot or PVC to export"),
+				mcp.Required()),
+			mcp.WithString(
+				"source_kind",
+				mcp.Description("Optional kind of the object to export, defaults to vm"),
+				mcp.Enum("vm", "snapshot", "pvc")),
+			mcp.WithString(
+				"export_name",
+				mcp.Description("Optional name of the export, generated from the source name by default")),
+			mcp.WithString(
+				"ttl",
+				mcp.Description("Optional time after which the export is removed, e.g. 30m or 4h (default 2h)")),
+			mcp.WithBoolean(
+				"include_cert",
+				mcp.Description("Optionally return the PEM encoded certificate of a self signed export server (default false)")),
 		),
-		resources.InstancetypesList,
+		vm.Export,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://{namespace}/preferences",
-			"Preferences",
-			mcp.WithTemplateDescription("List of VM preferences in a namespace"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"set_vm_instancetype",
+			mcp.WithDescription("change the instance type of a virtual machine after checking it exists and satisfies the requirements of the virtual machine preference, reporting whether the change applies live or requires a restart"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"instancetype",
+				mcp.Description("The name of the instance type"),
+				mcp.Required()),
+			mcp.WithString(
+				"kind",
+				mcp.Description("Optional kind of the instance type, defaults to VirtualMachineClusterInstancetype"),
+				mcp.Enum("VirtualMachineClusterInstancetype", "VirtualMachineInstancetype")),
+			mcp.WithBoolean(
+				"restart",
+				mcp.Description("Restart the virtual machine when the change cannot be applied live (default false)")),
 		),
-		resources.PreferencesList,
+		vm.SetInstancetype,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://cluster/instancetypes",
-			"Cluster Instance Types",
-			mcp.WithTemplateDescription("List of cluster-wide instance types"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"set_vm_preference",
+			mcp.WithDescription("change the preference of a virtual machine after checking it exists and its requirements are met by the virtual machine, reporting whether the change applies live or requires a restart"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"preference",
+				mcp.Description("The name of the preference"),
+				mcp.Required()),
+			mcp.WithString(
+				"kind",
+				mcp.Description("Optional kind of the preference, defaults to VirtualMachineClusterPreference"),
+				mcp.Enum("VirtualMachineClusterPreference", "VirtualMachinePreference")),
+			mcp.WithBoolean(
+				"restart",
+				mcp.Description("Restart the virtual machine when the change cannot be applied live (default false)")),
 		),
-		resources.ClusterInstancetypesList,
+		vm.SetPreference,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://cluster/preferences",
-			"Cluster Preferences",
-			mcp.WithTemplateDescription("List of cluster-wide VM preferences"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"resize_vm",
+			mcp.WithDescription("hotplug CPU sockets and guest memory of a virtual machine within its maxSockets and maxGuest limits, requires the LiveUpdate rollout strategy and reports the topology before and after the change"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithNumber(
+				"sockets",
+				mcp.Description("Optional number of vCPU sockets")),
+			mcp.WithString(
+				"memory",
+				mcp.Description("Optional guest memory, e.g. 4Gi")),
 		),
-		resources.ClusterPreferencesList,
+		vm.Resize,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://cluster/instancetype/{name}",
-			"Cluster Instance Type",
-			mcp.WithTemplateDescription("Individual cluster instance type specification"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"add_vm_interface",
+			mcp.WithDescription("hotplug a bridge bound network interface backed by a Multus NetworkAttachmentDefinition into a virtual machine and confirm it in the virtual machine instance interface status"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"interface_name",
+				mcp.Description("The name of the new interface and of the network it is paired with"),
+				mcp.Required()),
+			mcp.WithString(
+				"network_attachment_definition",
+				mcp.Description("The NetworkAttachmentDefinition backing the interface, as name or namespace/name"),
+				mcp.Required()),
+			mcp.WithString(
+				"mac_address",
+				mcp.Description("Optional MAC address of the interface")),
 		),
-		resources.ClusterInstancetypeGet,
+		vm.AddInterface,
 	)
 
-	s.AddResourceTemplate(
-		mcp.NewResourceTemplate(
-			"kubevirt://cluster/preference/{name}",
-			"Cluster Preference",
-			mcp.WithTemplateDescription("Individual cluster preference specification"),
-			mcp.WithTemplateMIMEType("application/json"),
+	s.AddTool(
+		mcp.NewTool(
+			"remove_vm_interface",
+			mcp.WithDescription("hot-unplug a bridge bound secondary network interface from a virtual machine by marking it absent and confirm it is gone from the virtual machine instance interface status"),
+			mcp.WithString(
+				"namespace",
+				mcp.Description("The namespace of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"name",
+				mcp.Description("The name of the virtual machine"),
+				mcp.Required()),
+			mcp.WithString(
+				"interface_name",
+				mcp.Description("The name of the interface to remove"),
+				mcp.Required()),
 		),
-		resources.ClusterPreferenceGet,
+		vm.RemoveInterface,
 	)
 
+	// Add MCP Resource Templates, all served by resources.Route which
+	// dispatches each URI to the handler of its most specific template
+	for _, template := range resources.Templates() {
+		s.AddResourceTemplate(template, resources.Route)
+	}
+
 	// Add MCP Prompts
 	s.AddPrompt(
 		mcp.NewPrompt(
@@ -0,0 +1,270 @@
+package inventory
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/pagination"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+// VM is the summary of a single virtual machine within the inventory
+type VM struct {
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	RunStrategy  string `json:"runStrategy,omitempty"`
+	Instancetype string `json:"instanceType,omitempty"`
+}
+
+// Namespace groups the virtual machines found within a single namespace
+type Namespace struct {
+	Namespace string `json:"namespace"`
+	// CountInPage counts the virtual machines of the namespace returned on
+	// this page, a namespace spanning several pages is counted on each
+	CountInPage int  `json:"countInPage"`
+	VMs         []VM `json:"vms"`
+}
+
+// Failure records a namespace that could not be read
+type Failure struct {
+	Namespace string `json:"namespace"`
+	Error     string `json:"error"`
+}
+
+// Result is a virtual machine inventory spanning one or more namespaces
+type Result struct {
+	Total      int         `json:"total"`
+	Namespaces []Namespace `json:"namespaces"`
+	Unreadable []Failure   `json:"unreadable,omitempty"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+	// Omitted counts virtual machines dropped to fit the response budget
+	Omitted int `json:"omitted,omitempty"`
+
+	// Continuation describes how to fetch the rest of the inventory
+	Continuation pagination.Continuation `json:"-"`
+}
+
+// ListVMs builds a virtual machine inventory grouped by namespace. When no
+// namespaces are given every namespace is listed, falling back to listing the
+// namespaces one by one when the caller cannot list cluster wide.
+func ListVMs(ctx context.Context, virtClient kubecli.KubevirtClient, namespaces []string, page pagination.Page) (*Result, error) {
+	if len(namespaces) == 0 {
+		return listAllNamespaces(ctx, virtClient, page)
+	}
+	return listNamespaces(ctx, virtClient, namespaces, page)
+}
+
+func listAllNamespaces(ctx context.Context, virtClient kubecli.KubevirtClient, page pagination.Page) (*Result, error) {
+	vms, err := virtClient.VirtualMachine(metav1.NamespaceAll).List(ctx, page.ListOptions())
+	if err == nil {
+		items := pagination.Window(page, vms.Items)
+		returned, err := pagination.Fit(len(items), func(n int) (int, error) {
+			data, err := json.MarshalIndent(Group(items[:n]), "", "  ")
+			return len(data), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		continuation := page.Continuation(vms.ListMeta, returned, len(items))
+		result := &Result{
+			Total:        returned,
+			Namespaces:   Group(items[:returned]),
+			NextCursor:   continuation.Cursor,
+			Omitted:      continuation.Omitted,
+			Continuation: continuation,
+		}
+		return result, nil
+	}
+	if !isPermissionError(err) {
+		return nil, err
+	}
+
+	// The caller cannot list cluster wide, try each namespace it can see instead
+	namespaceList, nsErr := virtClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if nsErr != nil {
+		return nil, fmt.Errorf("unable to list virtual machines across all namespaces: %w", err)
+	}
+	namespaces := make([]string, 0, len(namespaceList.Items))
+	for _, ns := range namespaceList.Items {
+		namespaces = append(namespaces, ns.Name)
+	}
+	return listNamespaces(ctx, virtClient, namespaces, page)
+}
+
+// listNamespaces lists the namespaces one by one in name order, filling the
+// page from as many namespaces as needed. The cursor records the namespace
+// to resume from along with the continue token within it.
+func listNamespaces(ctx context.Context, virtClient kubecli.KubevirtClient, namespaces []string, page pagination.Page) (*Result, error) {
+	namespaces = slices.Clone(namespaces)
+	slices.Sort(namespaces)
+	namespaces = slices.Compact(namespaces)
+
+	resume, err := decodePosition(page.Continue)
+	if err != nil {
+		return nil, err
+	}
+	start := 0
+	if resume.Namespace != "" {
+		var found bool
+		start, found = slices.BinarySearch(namespaces, resume.Namespace)
+		if !found {
+			return nil, fmt.Errorf("invalid cursor: namespace %s is not being listed", resume.Namespace)
+		}
+	}
+
+	result := &Result{
+		Namespaces: []Namespace{},
+	}
+	var (
+		items []virtv1.VirtualMachine
+		empty []emptyNamespace
+		next  position
+		read  int
+	)
+	for i := start; i < len(namespaces); i++ {
+		namespace := namespaces[i]
+		options := metav1.ListOptions{}
+		if page.Limit > 0 {
+			options.Limit = page.Limit - int64(len(items))
+		}
+		if i == start {
+			options.Continue = resume.Continue
+		}
+		vms, err := virtClient.VirtualMachine(namespace).List(ctx, options)
+		read++
+		if err != nil {
+			result.Unreadable = append(result.Unreadable, Failure{
+				Namespace: namespace,
+				Error:     err.Error(),
+			})
+			continue
+		}
+		// Keep namespaces without any virtual machines in the inventory
+		if len(vms.Items) == 0 && options.Continue == "" {
+			empty = append(empty, emptyNamespace{name: namespace, offset: len(items)})
+		}
+		items = append(items, vms.Items...)
+		if vms.Continue != "" {
+			next = position{Namespace: namespace, Continue: vms.Continue}
+			break
+		}
+		if page.Limit > 0 && int64(len(items)) >= page.Limit && i+1 < len(namespaces) {
+			next = position{Namespace: namespaces[i+1]}
+			break
+		}
+	}
+
+	if read > 0 && len(result.Unreadable) == read {
+		return nil, fmt.Errorf("unable to read any of the requested namespaces, first error: %s", result.Unreadable[0].Error)
+	}
+
+	items = pagination.Window(page, items)
+	returned, err := pagination.Fit(len(items), func(n int) (int, error) {
+		data, err := json.MarshalIndent(Group(items[:n]), "", "  ")
+		return len(data), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// An empty namespace sits between the items around its offset, it belongs
+	// to the page covering that gap so that re-fetching the remainder of a
+	// truncated page with a skip cursor does not report it twice
+	for _, namespace := range empty {
+		if namespace.offset >= page.Skip && (namespace.offset < page.Skip+returned || returned == len(items)) {
+			result.Namespaces = append(result.Namespaces, Namespace{Namespace: namespace.name, VMs: []VM{}})
+		}
+	}
+	continuation := page.Continuation(metav1.ListMeta{Continue: next.encode()}, returned, len(items))
+
+	result.Total = returned
+	result.NextCursor = continuation.Cursor
+	result.Omitted = continuation.Omitted
+	result.Continuation = continuation
+	result.Namespaces = append(result.Namespaces, Group(items[:returned])...)
+	sort.Slice(result.Namespaces, func(i, j int) bool {
+		return result.Namespaces[i].Namespace < result.Namespaces[j].Namespace
+	})
+	return result, nil
+}
+
+// emptyNamespace is a namespace without virtual machines, found after offset
+// items had been listed
+type emptyNamespace struct {
+	name   string
+	offset int
+}
+
+// position is where listing an explicit set of namespaces resumes
+type position struct {
+	Namespace string `json:"namespace"`
+	Continue  string `json:"continue,omitempty"`
+}
+
+// encode returns the position as a continue token, base64 encoded so that it
+// never contains the colon separating skipped items in a cursor
+func (p position) encode() string {
+	if p.Namespace == "" {
+		return ""
+	}
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePosition(token string) (position, error) {
+	var p position
+	if token == "" {
+		return p, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(data, &p) != nil || p.Namespace == "" {
+		return position{}, fmt.Errorf("invalid cursor %q", token)
+	}
+	return p, nil
+}
+
+// Group summarises virtual machines and groups them by namespace
+func Group(vms []virtv1.VirtualMachine) []Namespace {
+	byNamespace := map[string]*Namespace{}
+	for _, vm := range vms {
+		group, ok := byNamespace[vm.Namespace]
+		if !ok {
+			group = &Namespace{Namespace: vm.Namespace}
+			byNamespace[vm.Namespace] = group
+		}
+		summary := VM{
+			Name:   vm.Name,
+			Status: string(vm.Status.PrintableStatus),
+		}
+		if vm.Spec.RunStrategy != nil {
+			summary.RunStrategy = string(*vm.Spec.RunStrategy)
+		}
+		if vm.Spec.Instancetype != nil {
+			summary.Instancetype = vm.Spec.Instancetype.Name
+		}
+		group.VMs = append(group.VMs, summary)
+		group.CountInPage++
+	}
+
+	groups := make([]Namespace, 0, len(byNamespace))
+	for _, group := range byNamespace {
+		sort.Slice(group.VMs, func(i, j int) bool {
+			return group.VMs[i].Name < group.VMs[j].Name
+		})
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Namespace < groups[j].Namespace
+	})
+	return groups
+}
+
+func isPermissionError(err error) bool {
+	return k8serrors.IsForbidden(err) || k8serrors.IsUnauthorized(err)
+}
@@ -0,0 +1,181 @@
+package inventory_test
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+	kubevirtfake "kubevirt.io/client-go/kubevirt/fake"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/inventory"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/pagination"
+)
+
+func newVM(namespace, name string, status virtv1.VirtualMachinePrintableStatus) virtv1.VirtualMachine {
+	return virtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Status: virtv1.VirtualMachineStatus{
+			PrintableStatus: status,
+		},
+	}
+}
+
+// newClient returns a client backed by fake clientsets holding the objects,
+// refusing to list virtual machines across all namespaces when forbidden
+func newClient(forbidden bool, objects ...runtime.Object) kubecli.KubevirtClient {
+	var namespaces []runtime.Object
+	var vms []runtime.Object
+	for _, object := range objects {
+		if _, ok := object.(*corev1.Namespace); ok {
+			namespaces = append(namespaces, object)
+		} else {
+			vms = append(vms, object)
+		}
+	}
+	kubevirtClientset := kubevirtfake.NewSimpleClientset(vms...)
+	if forbidden {
+		kubevirtClientset.PrependReactor("list", "virtualmachines", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetNamespace() != metav1.NamespaceAll {
+				return false, nil, nil
+			}
+			return true, nil, k8serrors.NewForbidden(schema.GroupResource{Group: "kubevirt.io", Resource: "virtualmachines"}, "", nil)
+		})
+	}
+	coreClientset := k8sfake.NewSimpleClientset(namespaces...)
+
+	virtClient := kubecli.NewMockKubevirtClient(gomock.NewController(GinkgoT()))
+	virtClient.EXPECT().VirtualMachine(gomock.Any()).DoAndReturn(func(namespace string) kubecli.VirtualMachineInterface {
+		return kubevirtClientset.KubevirtV1().VirtualMachines(namespace)
+	}).AnyTimes()
+	virtClient.EXPECT().CoreV1().Return(coreClientset.CoreV1()).AnyTimes()
+	return virtClient
+}
+
+func newNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func names(result *inventory.Result) map[string][]string {
+	byNamespace := map[string][]string{}
+	for _, group := range result.Namespaces {
+		byNamespace[group.Namespace] = []string{}
+		for _, vm := range group.VMs {
+			byNamespace[group.Namespace] = append(byNamespace[group.Namespace], vm.Name)
+		}
+	}
+	return byNamespace
+}
+
+var _ = Describe("Inventory", func() {
+	Describe("Group", func() {
+		It("should return no groups for no virtual machines", func() {
+			Expect(inventory.Group(nil)).To(BeEmpty())
+		})
+
+		It("should group virtual machines by namespace with counts", func() {
+			runStrategy := virtv1.RunStrategyHalted
+			stopped := newVM("prod", "db", virtv1.VirtualMachineStatusStopped)
+			stopped.Spec.RunStrategy = &runStrategy
+			stopped.Spec.Instancetype = &virtv1.InstancetypeMatcher{Name: "u1.small"}
+
+			groups := inventory.Group([]virtv1.VirtualMachine{
+				newVM("dev", "web", virtv1.VirtualMachineStatusRunning),
+				stopped,
+				newVM("prod", "app", virtv1.VirtualMachineStatusRunning),
+			})
+
+			Expect(groups).To(HaveLen(2))
+			Expect(groups[0].Namespace).To(Equal("dev"))
+			Expect(groups[0].CountInPage).To(Equal(1))
+			Expect(groups[1].Namespace).To(Equal("prod"))
+			Expect(groups[1].CountInPage).To(Equal(2))
+			Expect(groups[1].VMs).To(Equal([]inventory.VM{
+				{Name: "app", Status: "Running"},
+				{Name: "db", Status: "Stopped", RunStrategy: "Halted", Instancetype: "u1.small"},
+			}))
+		})
+	})
+
+	Describe("ListVMs", func() {
+		var (
+			ctx context.Context
+			dev = newVM("dev", "web", virtv1.VirtualMachineStatusRunning)
+			ops = newVM("ops", "monitor", virtv1.VirtualMachineStatusRunning)
+			prd = newVM("prod", "db", virtv1.VirtualMachineStatusStopped)
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+		})
+
+		It("should fall back to listing each namespace when listing cluster wide is forbidden", func() {
+			virtClient := newClient(true, newNamespace("dev"), newNamespace("empty"), newNamespace("prod"), &dev, &prd)
+
+			result, err := inventory.ListVMs(ctx, virtClient, nil, pagination.Page{Limit: pagination.DefaultLimit})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Total).To(Equal(2))
+			Expect(names(result)).To(Equal(map[string][]string{
+				"dev":   {"web"},
+				"empty": {},
+				"prod":  {"db"},
+			}))
+			Expect(result.NextCursor).To(BeEmpty())
+		})
+
+		It("should page through an explicit set of namespaces with a cursor", func() {
+			virtClient := newClient(false, &dev, &ops, &prd)
+			namespaces := []string{"prod", "dev", "ops"}
+
+			first, err := inventory.ListVMs(ctx, virtClient, namespaces, pagination.Page{Limit: 2})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(first)).To(Equal(map[string][]string{"dev": {"web"}, "ops": {"monitor"}}))
+			Expect(first.NextCursor).NotTo(BeEmpty())
+
+			page, err := pagination.ParseCursor(first.NextCursor, 2)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := inventory.ListVMs(ctx, virtClient, namespaces, page)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(second)).To(Equal(map[string][]string{"prod": {"db"}}))
+			Expect(second.NextCursor).To(BeEmpty())
+		})
+
+		It("should report an empty namespace only on the page covering it", func() {
+			virtClient := newClient(false, &dev, &ops, &prd)
+			namespaces := []string{"dev", "empty", "ops", "prod"}
+
+			remainder, err := inventory.ListVMs(ctx, virtClient, namespaces, pagination.Page{Limit: 4, Skip: 1})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(remainder)).To(Equal(map[string][]string{"empty": {}, "ops": {"monitor"}, "prod": {"db"}}))
+
+			remainder, err = inventory.ListVMs(ctx, virtClient, namespaces, pagination.Page{Limit: 4, Skip: 2})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(remainder)).To(Equal(map[string][]string{"prod": {"db"}}))
+		})
+
+		It("should reject a cursor for a namespace that is not listed", func() {
+			virtClient := newClient(false, &dev)
+
+			_, err := inventory.ListVMs(ctx, virtClient, []string{"dev"}, pagination.Page{Limit: 2, Continue: "garbage"})
+
+			Expect(err).To(MatchError(ContainSubstring("invalid cursor")))
+		})
+	})
+})
@@ -0,0 +1,150 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Format is the encoding used when returning an object to the client
+type Format string
+
+const (
+	FormatJSON    Format = "json"
+	FormatYAML    Format = "yaml"
+	FormatSummary Format = "summary"
+)
+
+const (
+	MIMETypeJSON = "application/json"
+	MIMETypeYAML = "application/yaml"
+)
+
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Options controls how an object is rendered
+type Options struct {
+	Format Format
+	// Full disables stripping of managed fields, last-applied annotations and empty values
+	Full bool
+}
+
+// ParseFormat validates a format name, defaulting to json when empty
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case "":
+		return FormatJSON, nil
+	case FormatJSON, FormatYAML, FormatSummary:
+		return Format(format), nil
+	}
+	return "", fmt.Errorf("unsupported format %q, expected one of json, yaml or summary", format)
+}
+
+// ParseOptions builds options from the raw format and full values of a tool
+// argument or resource query parameter
+func ParseOptions(format, full string) (Options, error) {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return Options{}, err
+	}
+	opts := Options{Format: f}
+	if full != "" {
+		if opts.Full, err = strconv.ParseBool(full); err != nil {
+			return Options{}, fmt.Errorf("full must be a boolean, got %q", full)
+		}
+	}
+	return opts, nil
+}
+
+// Render encodes obj using the requested format and returns the text along
+// with its MIME type. The summary function is only called for the summary
+// format and its result is rendered as JSON.
+func Render(obj interface{}, summary func() interface{}, opts Options) (string, string, error) {
+	if opts.Format == FormatSummary {
+		obj = summary()
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", "", err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return "", "", err
+	}
+	if !opts.Full {
+		generic = Clean(generic)
+	}
+
+	if opts.Format == FormatYAML {
+		yamlData, err := yaml.Marshal(generic)
+		if err != nil {
+			return "", "", err
+		}
+		return string(yamlData), MIMETypeYAML, nil
+	}
+
+	jsonData, err := json.MarshalIndent(generic, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+	return string(jsonData), MIMETypeJSON, nil
+}
+
+// emptyContainers are fields that serialise as {} when unset and carry no
+// meaning when empty. Other empty objects are kept since KubeVirt uses them as
+// markers, for example the masquerade: {} interface binding or pod: {} network.
+var emptyContainers = map[string]bool{
+	"metadata":    true,
+	"spec":        true,
+	"status":      true,
+	"resources":   true,
+	"requests":    true,
+	"limits":      true,
+	"labels":      true,
+	"annotations": true,
+	"devices":     true,
+}
+
+// Clean strips managed fields, last-applied annotations, null values, empty
+// lists and empty container objects such as status: {} from a generic JSON
+// document
+func Clean(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		delete(v, "managedFields")
+		if annotations, ok := v["annotations"].(map[string]interface{}); ok {
+			delete(annotations, lastAppliedAnnotation)
+		}
+		for key, child := range v {
+			cleaned := Clean(child)
+			if isEmpty(key, cleaned) {
+				delete(v, key)
+				continue
+			}
+			v[key] = cleaned
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = Clean(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isEmpty(key string, value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0 && emptyContainers[key]
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package output_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/output"
+)
+
+var _ = Describe("Output", func() {
+	var vm *virtv1.VirtualMachine
+
+	BeforeEach(func() {
+		vm = &virtv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-vm",
+				Namespace: "test-ns",
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				},
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{Manager: "kubectl"},
+				},
+			},
+			Spec: virtv1.VirtualMachineSpec{
+				Template: &virtv1.VirtualMachineInstanceTemplateSpec{
+					Spec: virtv1.VirtualMachineInstanceSpec{
+						Domain: virtv1.DomainSpec{
+							Devices: virtv1.Devices{
+								Interfaces: []virtv1.Interface{
+									{
+										Name: "default",
+										InterfaceBindingMethod: virtv1.InterfaceBindingMethod{
+											Masquerade: &virtv1.InterfaceMasquerade{},
+										},
+									},
+								},
+							},
+						},
+						Networks: []virtv1.Network{
+							*virtv1.DefaultPodNetwork(),
+						},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("ParseFormat", func() {
+		It("should default to json", func() {
+			format, err := output.ParseFormat("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(format).To(Equal(output.FormatJSON))
+		})
+
+		It("should reject unknown formats", func() {
+			_, err := output.ParseFormat("xml")
+			Expect(err).To(MatchError(ContainSubstring(`unsupported format "xml"`)))
+		})
+	})
+
+	Describe("ParseOptions", func() {
+		It("should reject a non boolean full value", func() {
+			_, err := output.ParseOptions("yaml", "maybe")
+			Expect(err).To(MatchError(ContainSubstring("full must be a boolean")))
+		})
+	})
+
+	Describe("Render", func() {
+		summary := func() interface{} {
+			return output.VirtualMachineSummary(vm)
+		}
+
+		It("should strip managed fields, last-applied annotations and empty containers", func() {
+			text, mimeType, err := output.Render(vm, summary, output.Options{Format: output.FormatJSON})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mimeType).To(Equal(output.MIMETypeJSON))
+			Expect(text).To(ContainSubstring(`"name": "test-vm"`))
+			Expect(text).NotTo(ContainSubstring("managedFields"))
+			Expect(text).NotTo(ContainSubstring("last-applied-configuration"))
+			Expect(text).NotTo(ContainSubstring(`"status"`))
+			Expect(text).NotTo(ContainSubstring("creationTimestamp"))
+		})
+
+		It("should keep empty marker objects such as interface bindings", func() {
+			text, _, err := output.Render(vm, summary, output.Options{Format: output.FormatJSON})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(text).To(ContainSubstring(`"masquerade": {}`))
+			Expect(text).To(ContainSubstring(`"pod": {}`))
+		})
+
+		It("should keep everything when full output is requested", func() {
+			text, _, err := output.Render(vm, summary, output.Options{Format: output.FormatJSON, Full: true})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(text).To(ContainSubstring("managedFields"))
+			Expect(text).To(ContainSubstring("last-applied-configuration"))
+		})
+
+		It("should render yaml with the yaml MIME type", func() {
+			text, mimeType, err := output.Render(vm, summary, output.Options{Format: output.FormatYAML})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mimeType).To(Equal(output.MIMETypeYAML))
+			Expect(text).To(ContainSubstring("name: test-vm"))
+			Expect(text).To(ContainSubstring("masquerade: {}"))
+		})
+
+		It("should render the summary for the summary format", func() {
+			text, mimeType, err := output.Render(vm, summary, output.Options{Format: output.FormatSummary})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mimeType).To(Equal(output.MIMETypeJSON))
+			Expect(text).To(ContainSubstring(`"networks": [`))
+			Expect(text).NotTo(ContainSubstring("spec"))
+		})
+	})
+})
@@ -0,0 +1,299 @@
+package output
+
+import (
+	virtv1 "kubevirt.io/api/core/v1"
+	instancetypev1beta1 "kubevirt.io/api/instancetype/v1beta1"
+	snapshotv1beta1 "kubevirt.io/api/snapshot/v1beta1"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// VirtualMachineSummary condenses a virtual machine to the fields most useful to an agent
+func VirtualMachineSummary(vm *virtv1.VirtualMachine) map[string]interface{} {
+	summary := map[string]interface{}{
+		"name":      vm.Name,
+		"namespace": vm.Namespace,
+		"status":    vm.Status.PrintableStatus,
+		"ready":     vm.Status.Ready,
+		"created":   vm.CreationTimestamp,
+	}
+	if vm.Spec.RunStrategy != nil {
+		summary["runStrategy"] = string(*vm.Spec.RunStrategy)
+	}
+	if vm.Spec.Instancetype != nil {
+		summary["instanceType"] = vm.Spec.Instancetype.Name
+	}
+	if vm.Spec.Preference != nil {
+		summary["preference"] = vm.Spec.Preference.Name
+	}
+	if vm.Spec.Template != nil {
+		var disks []string
+		for _, disk := range vm.Spec.Template.Spec.Domain.Devices.Disks {
+			disks = append(disks, disk.Name)
+		}
+		if len(disks) > 0 {
+			summary["disks"] = disks
+		}
+		var networks []string
+		for _, network := range vm.Spec.Template.Spec.Networks {
+			networks = append(networks, network.Name)
+		}
+		if len(networks) > 0 {
+			summary["networks"] = networks
+		}
+	}
+	return summary
+}
+
+// VirtualMachineInstanceSummary condenses a virtual machine instance to its runtime state
+func VirtualMachineInstanceSummary(vmi *virtv1.VirtualMachineInstance) map[string]interface{} {
+	summary := map[string]interface{}{
+		"name":      vmi.Name,
+		"namespace": vmi.Namespace,
+		"phase":     vmi.Status.Phase,
+		"nodeName":  vmi.Status.NodeName,
+		"created":   vmi.CreationTimestamp,
+	}
+	if vmi.Status.GuestOSInfo.PrettyName != "" {
+		summary["guestOS"] = vmi.Status.GuestOSInfo.PrettyName
+	}
+	if len(vmi.Status.Interfaces) > 0 {
+		interfaces := make([]map[string]interface{}, 0, len(vmi.Status.Interfaces))
+		for _, iface := range vmi.Status.Interfaces {
+			ifaceInfo := map[string]interface{}{
+				"name": iface.Name,
+			}
+			if iface.IP != "" {
+				ifaceInfo["ip"] = iface.IP
+			}
+			if iface.MAC != "" {
+				ifaceInfo["mac"] = iface.MAC
+			}
+			interfaces = append(interfaces, ifaceInfo)
+		}
+		summary["interfaces"] = interfaces
+	}
+	var conditions []map[string]interface{}
+	for _, cond := range vmi.Status.Conditions {
+		conditions = append(conditions, map[string]interface{}{
+			"type":   cond.Type,
+			"status": cond.Status,
+		})
+	}
+	if len(conditions) > 0 {
+		summary["conditions"] = conditions
+	}
+	return summary
+}
+
+// DataVolumeSummary condenses a data volume to its source, storage and progress
+func DataVolumeSummary(dv *cdiv1beta1.DataVolume) map[string]interface{} {
+	summary := map[string]interface{}{
+		"name":      dv.Name,
+		"namespace": dv.Namespace,
+		"phase":     dv.Status.Phase,
+		"created":   dv.CreationTimestamp,
+	}
+	if source := DataVolumeSource(dv); len(source) > 0 {
+		summary["source"] = source
+	}
+	if storage := DataVolumeStorage(dv); len(storage) > 0 {
+		summary["storage"] = storage
+	}
+	if dv.Status.Progress != "" {
+		summary["progress"] = dv.Status.Progress
+	}
+	return summary
+}
+
+// DataVolumeSource describes where the data volume imports its data from
+func DataVolumeSource(dv *cdiv1beta1.DataVolume) map[string]interface{} {
+	source := map[string]interface{}{}
+	if dv.Spec.Source == nil {
+		return source
+	}
+	if dv.Spec.Source.HTTP != nil {
+		source["type"] = "http"
+		source["url"] = dv.Spec.Source.HTTP.URL
+	} else if dv.Spec.Source.S3 != nil {
+		source["type"] = "s3"
+		source["url"] = dv.Spec.Source.S3.URL
+	} else if dv.Spec.Source.Registry != nil {
+		source["type"] = "registry"
+		source["url"] = dv.Spec.Source.Registry.URL
+	} else if dv.Spec.Source.PVC != nil {
+		source["type"] = "pvc"
+		source["name"] = dv.Spec.Source.PVC.Name
+		source["namespace"] = dv.Spec.Source.PVC.Namespace
+	} else if dv.Spec.Source.Upload != nil {
+		source["type"] = "upload"
+	} else if dv.Spec.Source.Blank != nil {
+		source["type"] = "blank"
+	}
+	return source
+}
+
+// DataVolumeStorage describes the requested size and storage class of the data volume
+func DataVolumeStorage(dv *cdiv1beta1.DataVolume) map[string]interface{} {
+	storage := map[string]interface{}{}
+	if dv.Spec.Storage == nil {
+		return storage
+	}
+	if dv.Spec.Storage.Resources.Requests != nil {
+		if storageSize, ok := dv.Spec.Storage.Resources.Requests["storage"]; ok {
+			storage["size"] = storageSize.String()
+		}
+	}
+	if dv.Spec.Storage.StorageClassName != nil {
+		storage["storageClass"] = *dv.Spec.Storage.StorageClassName
+	}
+	return storage
+}
+
+// InstancetypeSummary condenses an instance type to its guest visible resources
+func InstancetypeSummary(name string, spec instancetypev1beta1.VirtualMachineInstancetypeSpec) map[string]interface{} {
+	summary := map[string]interface{}{
+		"name":   name,
+		"cpu":    spec.CPU.Guest,
+		"memory": spec.Memory.Guest.String(),
+	}
+	if len(spec.GPUs) > 0 {
+		summary["gpus"] = len(spec.GPUs)
+	}
+	if len(spec.HostDevices) > 0 {
+		summary["hostDevices"] = len(spec.HostDevices)
+	}
+	return summary
+}
+
+// PreferenceSummary condenses a preference to its most commonly used settings
+func PreferenceSummary(name string, spec instancetypev1beta1.VirtualMachinePreferenceSpec) map[string]interface{} {
+	summary := map[string]interface{}{
+		"name": name,
+	}
+	if spec.CPU != nil && spec.CPU.PreferredCPUTopology != nil {
+		summary["preferredCPUTopology"] = *spec.CPU.PreferredCPUTopology
+	}
+	if spec.Devices != nil {
+		if spec.Devices.PreferredDiskBus != "" {
+			summary["preferredDiskBus"] = spec.Devices.PreferredDiskBus
+		}
+		if spec.Devices.PreferredInterfaceModel != "" {
+			summary["preferredInterfaceModel"] = spec.Devices.PreferredInterfaceModel
+		}
+	}
+	if spec.Machine != nil && spec.Machine.PreferredMachineType != "" {
+		summary["preferredMachineType"] = spec.Machine.PreferredMachineType
+	}
+	if spec.Requirements != nil {
+		requirements := map[string]interface{}{}
+		if spec.Requirements.CPU != nil {
+			requirements["cpu"] = spec.Requirements.CPU.Guest
+		}
+		if spec.Requirements.Memory != nil {
+			requirements["memory"] = spec.Requirements.Memory.Guest.String()
+		}
+		summary["requirements"] = requirements
+	}
+	return summary
+}
+
+// MigrationSummary condenses a virtual machine instance migration to its target and progress
+func MigrationSummary(migration *virtv1.VirtualMachineInstanceMigration) map[string]interface{} {
+	summary := map[string]interface{}{
+		"name":      migration.Name,
+		"namespace": migration.Namespace,
+		"vmi":       migration.Spec.VMIName,
+		"phase":     migration.Status.Phase,
+		"created":   migration.CreationTimestamp,
+	}
+	if state := migration.Status.MigrationState; state != nil {
+		for key, value := range MigrationStateSummary(state) {
+			summary[key] = value
+		}
+	}
+	return summary
+}
+
+// DataTransferredUnavailable stands in for the transfer progress of a
+// migration, which KubeVirt only exposes through metrics and not the API
+const DataTransferredUnavailable = "unavailable, KubeVirt reports it only through the kubevirt_vmi_migration_data_processed_bytes and kubevirt_vmi_migration_data_remaining_bytes metrics"
+
+// MigrationStateSummary describes the nodes, timing and outcome of a live migration
+func MigrationStateSummary(state *virtv1.VirtualMachineInstanceMigrationState) map[string]interface{} {
+	summary := map[string]interface{}{
+		"completed":       state.Completed,
+		"failed":          state.Failed,
+		"dataTransferred": DataTransferredUnavailable,
+	}
+	if state.SourceNode != "" {
+		summary["sourceNode"] = state.SourceNode
+	}
+	if state.TargetNode != "" {
+		summary["targetNode"] = state.TargetNode
+	}
+	if state.Mode != "" {
+		summary["mode"] = state.Mode
+	}
+	if state.StartTimestamp != nil {
+		summary["startTimestamp"] = state.StartTimestamp
+	}
+	if state.EndTimestamp != nil {
+		summary["endTimestamp"] = state.EndTimestamp
+	}
+	if state.FailureReason != "" {
+		summary["failureReason"] = state.FailureReason
+	}
+	if state.AbortRequested {
+		summary["abortRequested"] = true
+		summary["abortStatus"] = state.AbortStatus
+	}
+	return summary
+}
+
+// SnapshotSummary describes the readiness, indications and volumes of a
+// virtual machine snapshot. The snapshot content is optional and provides
+// the status of the individual volume snapshots.
+func SnapshotSummary(snapshot *snapshotv1beta1.VirtualMachineSnapshot, content *snapshotv1beta1.VirtualMachineSnapshotContent) map[string]interface{} {
+	summary := map[string]interface{}{
+		"name":      snapshot.Name,
+		"namespace": snapshot.Namespace,
+		"vm":        snapshot.Spec.Source.Name,
+		"created":   snapshot.CreationTimestamp,
+	}
+	if status := snapshot.Status; status != nil {
+		summary["phase"] = status.Phase
+		summary["readyToUse"] = status.ReadyToUse != nil && *status.ReadyToUse
+		if status.CreationTime != nil {
+			summary["creationTime"] = status.CreationTime
+		}
+		if len(status.Indications) > 0 {
+			summary["indications"] = status.Indications
+		}
+		if status.Error != nil && status.Error.Message != nil {
+			summary["error"] = *status.Error.Message
+		}
+		if volumes := status.SnapshotVolumes; volumes != nil {
+			if len(volumes.IncludedVolumes) > 0 {
+				summary["includedVolumes"] = volumes.IncludedVolumes
+			}
+			if len(volumes.ExcludedVolumes) > 0 {
+				summary["excludedVolumes"] = volumes.ExcludedVolumes
+			}
+		}
+	}
+	if content != nil && content.Status != nil && len(content.Status.VolumeSnapshotStatus) > 0 {
+		volumeSnapshots := make([]map[string]interface{}, 0, len(content.Status.VolumeSnapshotStatus))
+		for _, volumeStatus := range content.Status.VolumeSnapshotStatus {
+			volumeInfo := map[string]interface{}{
+				"name":       volumeStatus.VolumeSnapshotName,
+				"readyToUse": volumeStatus.ReadyToUse != nil && *volumeStatus.ReadyToUse,
+			}
+			if volumeStatus.Error != nil && volumeStatus.Error.Message != nil {
+				volumeInfo["error"] = *volumeStatus.Error.Message
+			}
+			volumeSnapshots = append(volumeSnapshots, volumeInfo)
+		}
+		summary["volumeSnapshots"] = volumeSnapshots
+	}
+	return summary
+}
@@ -0,0 +1,103 @@
+package pagination
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultMaxResponseBytes is the response budget used unless configured otherwise
+	DefaultMaxResponseBytes = 100000
+	// BytesPerToken approximates the number of bytes of JSON text per model token
+	BytesPerToken = 4
+)
+
+var maxResponseBytes atomic.Int64
+
+func init() {
+	maxResponseBytes.Store(DefaultMaxResponseBytes)
+}
+
+// SetMaxResponseBytes configures the byte budget of a single list response,
+// zero or less disables the budget
+func SetMaxResponseBytes(n int) {
+	maxResponseBytes.Store(int64(n))
+}
+
+// MaxResponseBytes returns the configured byte budget of a single list
+// response, zero when the budget is disabled
+func MaxResponseBytes() int {
+	n := maxResponseBytes.Load()
+	if n < 0 {
+		return 0
+	}
+	return int(n)
+}
+
+// Fit returns the number of leading items that can be returned within the
+// response budget. size reports the encoded size of a response holding the
+// first n items and must grow with n. At least one item is always returned
+// so that callers can make progress through a list of oversized items.
+func Fit(count int, size func(n int) (int, error)) (int, error) {
+	budget := MaxResponseBytes()
+	if budget == 0 || count <= 1 {
+		return count, nil
+	}
+
+	total, err := size(count)
+	if err != nil {
+		return 0, err
+	}
+	if total <= budget {
+		return count, nil
+	}
+
+	var sizeErr error
+	// Find the first n that no longer fits, the answer is the n before it
+	over := sort.Search(count+1, func(n int) bool {
+		if sizeErr != nil || n == 0 {
+			return sizeErr != nil
+		}
+		s, err := size(n)
+		if err != nil {
+			sizeErr = err
+			return true
+		}
+		return s > budget
+	})
+	if sizeErr != nil {
+		return 0, sizeErr
+	}
+	if over <= 1 {
+		return 1, nil
+	}
+	return over - 1, nil
+}
+
+// NameList renders the names of a fetched page one per line, skipping names
+// already returned and truncating to the response budget, followed by the
+// trailer telling the caller how to continue
+func NameList(page Page, listMeta metav1.ListMeta, names []string) string {
+	names = Window(page, names)
+	returned, _ := Fit(len(names), func(n int) (int, error) {
+		size := trailerOverhead
+		for _, name := range names[:n] {
+			size += len(name) + 1
+		}
+		return size, nil
+	})
+
+	var text strings.Builder
+	for _, name := range names[:returned] {
+		text.WriteString(name)
+		text.WriteString("\n")
+	}
+	text.WriteString(page.Continuation(listMeta, returned, len(names)).ToolTrailer())
+	return text.String()
+}
+
+// trailerOverhead reserves room within the response budget for the trailer
+const trailerOverhead = 512
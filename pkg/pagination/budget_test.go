@@ -0,0 +1,69 @@
+package pagination_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/pagination"
+)
+
+var _ = Describe("Budget", func() {
+	BeforeEach(func() {
+		DeferCleanup(pagination.SetMaxResponseBytes, pagination.MaxResponseBytes())
+	})
+
+	Describe("Fit", func() {
+		tenBytesEach := func(n int) (int, error) {
+			return n * 10, nil
+		}
+
+		It("should return every item when they fit", func() {
+			pagination.SetMaxResponseBytes(100)
+
+			Expect(pagination.Fit(10, tenBytesEach)).To(Equal(10))
+		})
+
+		It("should return the largest prefix that fits", func() {
+			pagination.SetMaxResponseBytes(45)
+
+			Expect(pagination.Fit(10, tenBytesEach)).To(Equal(4))
+		})
+
+		It("should always return at least one item", func() {
+			pagination.SetMaxResponseBytes(5)
+
+			Expect(pagination.Fit(10, tenBytesEach)).To(Equal(1))
+		})
+
+		It("should return every item when the budget is disabled", func() {
+			pagination.SetMaxResponseBytes(0)
+
+			Expect(pagination.Fit(1000, tenBytesEach)).To(Equal(1000))
+		})
+	})
+
+	Describe("NameList", func() {
+		It("should truncate names and continue within the same page", func() {
+			pagination.SetMaxResponseBytes(600)
+			names := make([]string, 10)
+			for i := range names {
+				names[i] = strings.Repeat("a", 40)
+			}
+
+			text := pagination.NameList(pagination.Page{Limit: 10}, metav1.ListMeta{}, names)
+
+			Expect(strings.Count(text, strings.Repeat("a", 40))).To(Equal(2))
+			Expect(text).To(ContainSubstring("8 results omitted to stay within the 600 byte response budget"))
+			Expect(text).To(ContainSubstring("cursor: 2:"))
+		})
+
+		It("should skip names returned by an earlier response", func() {
+			text := pagination.NameList(pagination.Page{Limit: 10, Skip: 2}, metav1.ListMeta{}, []string{"a", "b", "c"})
+
+			Expect(text).To(Equal("c\n"))
+		})
+	})
+})
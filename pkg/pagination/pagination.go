@@ -0,0 +1,208 @@
+package pagination
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultLimit is the page size used when the caller does not provide one
+	DefaultLimit int64 = 100
+	// MaxLimit caps the page size a caller can request in a single call
+	MaxLimit int64 = 500
+)
+
+// Page describes a single page of a Kubernetes list call. Continue is the
+// opaque Kubernetes continue token returned by the previous page and Skip the
+// number of items of this page already returned by an earlier response that
+// was truncated to fit the response budget.
+type Page struct {
+	Limit    int64
+	Continue string
+	Skip     int
+}
+
+// ListOptions converts the page into Kubernetes list options
+func (p Page) ListOptions() metav1.ListOptions {
+	return metav1.ListOptions{
+		Limit:    p.Limit,
+		Continue: p.Continue,
+	}
+}
+
+// Cursor encodes the page as the opaque cursor handed back to callers. Pages
+// without skipped items use the Kubernetes continue token as is, otherwise the
+// number of skipped items and the limit of the page are prefixed, which is
+// unambiguous as continue tokens are base64 encoded and never contain a
+// colon. The skipped items only line up when the page is fetched again with
+// the same limit.
+func (p Page) Cursor() string {
+	if p.Skip == 0 {
+		return p.Continue
+	}
+	return fmt.Sprintf("%d:%d:%s", p.Skip, p.Limit, p.Continue)
+}
+
+// ParseCursor decodes a cursor produced by Page.Cursor into a page of the
+// given limit. A zero limit takes the limit the cursor was issued with, or
+// DefaultLimit, while a cursor issued with a different limit is rejected.
+func ParseCursor(cursor string, limit int64) (Page, error) {
+	page := Page{Limit: limit, Continue: cursor}
+	if page.Limit == 0 {
+		page.Limit = DefaultLimit
+	}
+	skip, rest, found := strings.Cut(cursor, ":")
+	if !found {
+		return page, nil
+	}
+	issuedLimit, rest, found := strings.Cut(rest, ":")
+	if !found {
+		return Page{}, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	n, err := strconv.Atoi(skip)
+	if err != nil || n < 1 {
+		return Page{}, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	issued, err := strconv.ParseInt(issuedLimit, 10, 64)
+	if err != nil || issued < 1 {
+		return Page{}, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	if limit != 0 && limit != issued {
+		return Page{}, fmt.Errorf("cursor was issued with limit %d, call again with the same limit or without one", issued)
+	}
+	page.Limit = issued
+	page.Skip = n
+	page.Continue = rest
+	return page, nil
+}
+
+// Window drops the items of a fetched page that were already returned by an
+// earlier truncated response
+func Window[T any](page Page, items []T) []T {
+	if page.Skip >= len(items) {
+		return items[:0]
+	}
+	return items[page.Skip:]
+}
+
+// FromToolRequest reads the optional limit and cursor arguments of a tool call
+func FromToolRequest(request mcp.CallToolRequest) (Page, error) {
+	var limit int64
+
+	args := request.GetArguments()
+	if raw, ok := args["limit"]; ok && raw != nil {
+		value, ok := raw.(float64)
+		if !ok || value != float64(int64(value)) {
+			return Page{}, fmt.Errorf("limit parameter must be an integer")
+		}
+		var err error
+		if limit, err = validateLimit(int64(value)); err != nil {
+			return Page{}, err
+		}
+	}
+	cursor := ""
+	if raw, ok := args["cursor"]; ok && raw != nil {
+		if cursor, ok = raw.(string); !ok {
+			return Page{}, fmt.Errorf("cursor parameter must be a string")
+		}
+	}
+	return ParseCursor(cursor, limit)
+}
+
+// FromQuery reads the optional limit and cursor values of decoded query parameters
+func FromQuery(query map[string]string) (Page, error) {
+	var limit int64
+
+	if value := query["limit"]; value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return Page{}, fmt.Errorf("limit query parameter must be an integer: %s", value)
+		}
+		if limit, err = validateLimit(parsed); err != nil {
+			return Page{}, err
+		}
+	}
+	return ParseCursor(query["cursor"], limit)
+}
+
+// Continuation describes how a caller fetches the rest of a list after a
+// response that was either a partial page or truncated to fit the budget
+type Continuation struct {
+	// Cursor fetches the next items, empty once the list has been exhausted
+	Cursor string
+	// Omitted counts the items of the fetched page dropped to fit the budget
+	Omitted int
+	// Remaining counts the items beyond the fetched page, when the server reports it
+	Remaining *int64
+}
+
+// Continuation returns how to continue after returning the first returned of
+// the fetched items of this page, given the list metadata of the page
+func (p Page) Continuation(listMeta metav1.ListMeta, returned, fetched int) Continuation {
+	if returned < fetched {
+		return Continuation{
+			Cursor:    Page{Limit: p.Limit, Continue: p.Continue, Skip: p.Skip + returned}.Cursor(),
+			Omitted:   fetched - returned,
+			Remaining: listMeta.RemainingItemCount,
+		}
+	}
+	if listMeta.Continue == "" {
+		return Continuation{}
+	}
+	return Continuation{
+		Cursor:    listMeta.Continue,
+		Remaining: listMeta.RemainingItemCount,
+	}
+}
+
+// NextURI returns the resource URI continuing the list, or an empty string
+// when the list has been exhausted
+func (c Continuation) NextURI(uri string, page Page) (string, error) {
+	if c.Cursor == "" {
+		return "", nil
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid URI: %w", err)
+	}
+	query := parsed.Query()
+	query.Set("limit", strconv.FormatInt(page.Limit, 10))
+	query.Set("cursor", c.Cursor)
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String(), nil
+}
+
+// ToolTrailer returns the text appended to a paginated tool result telling the
+// caller how to fetch the rest of the list, or an empty string once exhausted
+func (c Continuation) ToolTrailer() string {
+	if c.Cursor == "" {
+		return ""
+	}
+	var trailer string
+	switch {
+	case c.Omitted > 0:
+		trailer = fmt.Sprintf("%d results omitted to stay within the %d byte response budget", c.Omitted, MaxResponseBytes())
+		if c.Remaining != nil {
+			trailer += fmt.Sprintf(", %d more results after them", *c.Remaining)
+		}
+	case c.Remaining != nil:
+		trailer = fmt.Sprintf("%d more results available", *c.Remaining)
+	default:
+		trailer = "more results available"
+	}
+	return fmt.Sprintf("%s, call again with cursor: %s\n", trailer, c.Cursor)
+}
+
+func validateLimit(limit int64) (int64, error) {
+	if limit < 1 || limit > MaxLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d, got %d", MaxLimit, limit)
+	}
+	return limit, nil
+}
@@ -0,0 +1,194 @@
+package pagination_test
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/pagination"
+)
+
+var _ = Describe("Pagination", func() {
+	Describe("FromToolRequest", func() {
+		It("should use the default limit when no arguments are given", func() {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{}
+
+			page, err := pagination.FromToolRequest(request)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Limit).To(Equal(pagination.DefaultLimit))
+			Expect(page.Continue).To(BeEmpty())
+		})
+
+		It("should read limit and cursor arguments", func() {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{
+				"limit":  float64(10),
+				"cursor": "token",
+			}
+
+			page, err := pagination.FromToolRequest(request)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.ListOptions()).To(Equal(metav1.ListOptions{Limit: 10, Continue: "token"}))
+		})
+
+		It("should reject a limit outside the allowed range", func() {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{
+				"limit": float64(pagination.MaxLimit + 1),
+			}
+
+			_, err := pagination.FromToolRequest(request)
+
+			Expect(err).To(MatchError(ContainSubstring("limit must be between")))
+		})
+
+		It("should reject a non-integer limit", func() {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{
+				"limit": 2.5,
+			}
+
+			_, err := pagination.FromToolRequest(request)
+
+			Expect(err).To(MatchError(ContainSubstring("limit parameter must be an integer")))
+		})
+
+		It("should resume a truncated page with the limit it was issued with", func() {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{
+				"cursor": "2:10:token",
+			}
+
+			page, err := pagination.FromToolRequest(request)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(Equal(pagination.Page{Limit: 10, Continue: "token", Skip: 2}))
+		})
+
+		It("should reject a truncated page cursor with a different limit", func() {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{
+				"limit":  float64(20),
+				"cursor": "2:10:token",
+			}
+
+			_, err := pagination.FromToolRequest(request)
+
+			Expect(err).To(MatchError(ContainSubstring("cursor was issued with limit 10")))
+		})
+	})
+
+	Describe("FromQuery", func() {
+		It("should read limit and cursor query parameters", func() {
+			page, err := pagination.FromQuery(map[string]string{"limit": "5", "cursor": "abc="})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Limit).To(Equal(int64(5)))
+			Expect(page.Continue).To(Equal("abc="))
+			Expect(page.Skip).To(BeZero())
+		})
+
+		It("should default the limit", func() {
+			page, err := pagination.FromQuery(map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(Equal(pagination.Page{Limit: pagination.DefaultLimit}))
+		})
+
+		It("should decode the skipped items and limit of a truncated page", func() {
+			page, err := pagination.FromQuery(map[string]string{"limit": "5", "cursor": "3:5:abc="})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(Equal(pagination.Page{Limit: 5, Continue: "abc=", Skip: 3}))
+		})
+
+		It("should take the limit of a truncated page cursor when none is given", func() {
+			page, err := pagination.FromQuery(map[string]string{"cursor": "3:5:abc="})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(Equal(pagination.Page{Limit: 5, Continue: "abc=", Skip: 3}))
+		})
+
+		It("should reject a truncated page cursor with a different limit", func() {
+			_, err := pagination.FromQuery(map[string]string{"limit": "10", "cursor": "3:5:abc="})
+
+			Expect(err).To(MatchError(ContainSubstring("cursor was issued with limit 5")))
+		})
+
+		It("should reject a malformed cursor", func() {
+			_, err := pagination.FromQuery(map[string]string{"cursor": "x:5:abc"})
+
+			Expect(err).To(MatchError(ContainSubstring("invalid cursor")))
+		})
+
+		It("should reject a cursor without a limit", func() {
+			_, err := pagination.FromQuery(map[string]string{"cursor": "3:abc="})
+
+			Expect(err).To(MatchError(ContainSubstring("invalid cursor")))
+		})
+
+		It("should reject an invalid limit", func() {
+			_, err := pagination.FromQuery(map[string]string{"limit": "lots"})
+
+			Expect(err).To(MatchError(ContainSubstring("limit query parameter must be an integer")))
+		})
+	})
+
+	Describe("Continuation", func() {
+		It("should be empty on the last page", func() {
+			continuation := pagination.Page{Limit: 5}.Continuation(metav1.ListMeta{}, 2, 2)
+
+			Expect(continuation.Cursor).To(BeEmpty())
+			Expect(continuation.ToolTrailer()).To(BeEmpty())
+		})
+
+		It("should continue from the next page when every item was returned", func() {
+			continuation := pagination.Page{Limit: 5}.Continuation(metav1.ListMeta{Continue: "a/b="}, 5, 5)
+
+			Expect(continuation.Cursor).To(Equal("a/b="))
+			Expect(continuation.Omitted).To(BeZero())
+		})
+
+		It("should continue within the same page when items were omitted", func() {
+			page := pagination.Page{Limit: 5, Continue: "token", Skip: 1}
+			continuation := page.Continuation(metav1.ListMeta{Continue: "later"}, 2, 4)
+
+			Expect(continuation.Cursor).To(Equal("3:5:token"))
+			Expect(continuation.Omitted).To(Equal(2))
+
+			resumed, err := pagination.ParseCursor(continuation.Cursor, 5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resumed).To(Equal(pagination.Page{Limit: 5, Continue: "token", Skip: 3}))
+		})
+
+		It("should encode the cursor into the next URI", func() {
+			continuation := pagination.Page{Limit: 5}.Continuation(metav1.ListMeta{Continue: "a/b="}, 5, 5)
+
+			next, err := continuation.NextURI("kubevirt://default/vms?limit=5", pagination.Page{Limit: 5})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal("kubevirt://default/vms?cursor=a%2Fb%3D&limit=5"))
+		})
+
+		It("should include the cursor and remaining count in the tool trailer", func() {
+			remaining := int64(42)
+			continuation := pagination.Page{Limit: 5}.Continuation(metav1.ListMeta{Continue: "token", RemainingItemCount: &remaining}, 5, 5)
+
+			trailer := continuation.ToolTrailer()
+
+			Expect(trailer).To(ContainSubstring("42 more results available"))
+			Expect(trailer).To(ContainSubstring("cursor: token"))
+		})
+
+		It("should report omitted items in the tool trailer", func() {
+			continuation := pagination.Page{Limit: 5}.Continuation(metav1.ListMeta{}, 3, 5)
+
+			Expect(continuation.ToolTrailer()).To(ContainSubstring("2 results omitted to stay within"))
+			Expect(continuation.ToolTrailer()).To(ContainSubstring("cursor: 3:"))
+		})
+	})
+})
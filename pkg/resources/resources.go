@@ -4,27 +4,35 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
-	"strings"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/inventory"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/output"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/pagination"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func VmsList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace from URI: kubevirt://{namespace}/vms
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 3 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/vms")
+	uri, err := vmsTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	namespace := uri.Vars["namespace"]
+
+	page, err := pagination.FromQuery(uri.Query)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	vms, err := virtClient.VirtualMachine(namespace).List(ctx, metav1.ListOptions{})
+	vms, err := virtClient.VirtualMachine(namespace).List(ctx, page.ListOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +54,42 @@ func VmsList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.Resour
 		vmList = append(vmList, vmInfo)
 	}
 
-	jsonData, err := json.MarshalIndent(vmList, "", "  ")
+	return paginatedContents(request.Params.URI, page, vms.ListMeta, vmList)
+}
+
+func AllVmsList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri, err := allVmsTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := pagination.FromQuery(uri.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := inventory.ListVMs(ctx, virtClient, nil, page)
+	if err != nil {
+		return nil, err
+	}
+
+	inventoryInfo := map[string]interface{}{
+		"total":      result.Total,
+		"namespaces": result.Namespaces,
+	}
+	if len(result.Unreadable) > 0 {
+		inventoryInfo["unreadable"] = result.Unreadable
+	}
+	if err := addContinuation(inventoryInfo, request.Params.URI, page, result.Continuation); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(inventoryInfo, "", "  ")
 	if err != nil {
 		return nil, err
 	}
@@ -61,52 +104,51 @@ func VmsList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.Resour
 }
 
 func VmGet(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace and name from URI: kubevirt://{namespace}/vm/{name}
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 5 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/vm/{name}")
+	uri, err := vmTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
-	name := parts[4]
+	namespace := uri.Vars["namespace"]
+	name := uri.Vars["name"]
 
-	virtClient, err := client.GetKubevirtClient()
+	opts, err := output.ParseOptions(uri.Query["format"], uri.Query["full"])
 	if err != nil {
 		return nil, err
 	}
 
-	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	jsonData, err := json.MarshalIndent(vm, "", "  ")
+	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
-			MIMEType: "application/json",
-			Text:     string(jsonData),
-		},
-	}, nil
+	return renderedContents(request.Params.URI, vm, func() interface{} {
+		return output.VirtualMachineSummary(vm)
+	}, opts)
 }
 
 func VmisList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace from URI: kubevirt://{namespace}/vmis
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 3 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/vmis")
+	uri, err := vmisTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	namespace := uri.Vars["namespace"]
+
+	page, err := pagination.FromQuery(uri.Query)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	vmis, err := virtClient.VirtualMachineInstance(namespace).List(ctx, metav1.ListOptions{})
+	vmis, err := virtClient.VirtualMachineInstance(namespace).List(ctx, page.ListOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -139,60 +181,48 @@ func VmisList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.Resou
 		vmiList = append(vmiList, vmiInfo)
 	}
 
-	jsonData, err := json.MarshalIndent(vmiList, "", "  ")
-	if err != nil {
-		return nil, err
-	}
-
-	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
-			MIMEType: "application/json",
-			Text:     string(jsonData),
-		},
-	}, nil
+	return paginatedContents(request.Params.URI, page, vmis.ListMeta, vmiList)
 }
 
 func VmiGet(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace and name from URI: kubevirt://{namespace}/vmi/{name}
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 5 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/vmi/{name}")
+	uri, err := vmiTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
-	name := parts[4]
+	namespace := uri.Vars["namespace"]
+	name := uri.Vars["name"]
 
-	virtClient, err := client.GetKubevirtClient()
+	opts, err := output.ParseOptions(uri.Query["format"], uri.Query["full"])
 	if err != nil {
 		return nil, err
 	}
 
-	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	jsonData, err := json.MarshalIndent(vmi, "", "  ")
+	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
-			MIMEType: "application/json",
-			Text:     string(jsonData),
-		},
-	}, nil
+	return renderedContents(request.Params.URI, vmi, func() interface{} {
+		return output.VirtualMachineInstanceSummary(vmi)
+	}, opts)
 }
 
 func DataVolumesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace from URI: kubevirt://{namespace}/datavolumes
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 3 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/datavolumes")
+	uri, err := dataVolumesTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	namespace := uri.Vars["namespace"]
+
+	page, err := pagination.FromQuery(uri.Query)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -201,7 +231,7 @@ func DataVolumesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mc
 
 	// Get the underlying clientset to access CDI resources
 	clientset := virtClient.CdiClient()
-	dataVolumes, err := clientset.CdiV1beta1().DataVolumes(namespace).List(ctx, metav1.ListOptions{})
+	dataVolumes, err := clientset.CdiV1beta1().DataVolumes(namespace).List(ctx, page.ListOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -215,46 +245,11 @@ func DataVolumesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mc
 			"created":   dv.CreationTimestamp,
 		}
 
-		// Add source information if available
-		if dv.Spec.Source != nil {
-			source := map[string]interface{}{}
-			if dv.Spec.Source.HTTP != nil {
-				source["type"] = "http"
-				source["url"] = dv.Spec.Source.HTTP.URL
-			} else if dv.Spec.Source.S3 != nil {
-				source["type"] = "s3"
-				source["url"] = dv.Spec.Source.S3.URL
-			} else if dv.Spec.Source.Registry != nil {
-				source["type"] = "registry"
-				source["url"] = dv.Spec.Source.Registry.URL
-			} else if dv.Spec.Source.PVC != nil {
-				source["type"] = "pvc"
-				source["name"] = dv.Spec.Source.PVC.Name
-				source["namespace"] = dv.Spec.Source.PVC.Namespace
-			} else if dv.Spec.Source.Upload != nil {
-				source["type"] = "upload"
-			} else if dv.Spec.Source.Blank != nil {
-				source["type"] = "blank"
-			}
-			if len(source) > 0 {
-				dvInfo["source"] = source
-			}
+		if source := output.DataVolumeSource(&dv); len(source) > 0 {
+			dvInfo["source"] = source
 		}
-
-		// Add storage information
-		if dv.Spec.Storage != nil {
-			storage := map[string]interface{}{}
-			if dv.Spec.Storage.Resources.Requests != nil {
-				if storageSize, ok := dv.Spec.Storage.Resources.Requests["storage"]; ok {
-					storage["size"] = storageSize.String()
-				}
-			}
-			if dv.Spec.Storage.StorageClassName != nil {
-				storage["storageClass"] = *dv.Spec.Storage.StorageClassName
-			}
-			if len(storage) > 0 {
-				dvInfo["storage"] = storage
-			}
+		if storage := output.DataVolumeStorage(&dv); len(storage) > 0 {
+			dvInfo["storage"] = storage
 		}
 
 		// Add progress information if available
@@ -265,28 +260,21 @@ func DataVolumesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mc
 		dvList = append(dvList, dvInfo)
 	}
 
-	jsonData, err := json.MarshalIndent(dvList, "", "  ")
+	return paginatedContents(request.Params.URI, page, dataVolumes.ListMeta, dvList)
+}
+
+func DataVolumeGet(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri, err := dataVolumeTemplate.Match(request.Params.URI)
 	if err != nil {
 		return nil, err
 	}
+	namespace := uri.Vars["namespace"]
+	name := uri.Vars["name"]
 
-	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
-			MIMEType: "application/json",
-			Text:     string(jsonData),
-		},
-	}, nil
-}
-
-func DataVolumeGet(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace and name from URI: kubevirt://{namespace}/datavolume/{name}
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 5 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/datavolume/{name}")
+	opts, err := output.ParseOptions(uri.Query["format"], uri.Query["full"])
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
-	name := parts[4]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -300,28 +288,18 @@ func DataVolumeGet(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.
 		return nil, err
 	}
 
-	jsonData, err := json.MarshalIndent(dataVolume, "", "  ")
-	if err != nil {
-		return nil, err
-	}
-
-	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
-			MIMEType: "application/json",
-			Text:     string(jsonData),
-		},
-	}, nil
+	return renderedContents(request.Params.URI, dataVolume, func() interface{} {
+		return output.DataVolumeSummary(dataVolume)
+	}, opts)
 }
 
 func VmGetStatus(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace and name from URI: kubevirt://{namespace}/vm/{name}/status
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 6 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/vm/{name}/status")
+	uri, err := vmStatusTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
-	name := parts[4]
+	namespace := uri.Vars["namespace"]
+	name := uri.Vars["name"]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -399,13 +377,12 @@ func VmGetStatus(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.Re
 }
 
 func VmiGetGuestOSInfo(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace and name from URI: kubevirt://{namespace}/vmi/{name}/guestosinfo
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 6 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/vmi/{name}/guestosinfo")
+	uri, err := vmiGuestOSInfoTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
-	name := parts[4]
+	namespace := uri.Vars["namespace"]
+	name := uri.Vars["name"]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -432,13 +409,12 @@ func VmiGetGuestOSInfo(ctx context.Context, request mcp.ReadResourceRequest) ([]
 }
 
 func VmiGetFilesystems(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace and name from URI: kubevirt://{namespace}/vmi/{name}/filesystems
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 6 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/vmi/{name}/filesystems")
+	uri, err := vmiFilesystemsTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
-	name := parts[4]
+	namespace := uri.Vars["namespace"]
+	name := uri.Vars["name"]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -465,13 +441,12 @@ func VmiGetFilesystems(ctx context.Context, request mcp.ReadResourceRequest) ([]
 }
 
 func VmiGetUserList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace and name from URI: kubevirt://{namespace}/vmi/{name}/userlist
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 6 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/vmi/{name}/userlist")
+	uri, err := vmiUserListTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
-	name := parts[4]
+	namespace := uri.Vars["namespace"]
+	name := uri.Vars["name"]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -498,13 +473,12 @@ func VmiGetUserList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp
 }
 
 func VmGetConsole(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace and name from URI: kubevirt://{namespace}/vm/{name}/console
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 6 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/vm/{name}/console")
+	uri, err := vmConsoleTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
-	name := parts[4]
+	namespace := uri.Vars["namespace"]
+	name := uri.Vars["name"]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -544,7 +518,7 @@ func VmGetConsole(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.R
 	// Add connection details
 	if len(consoles) > 0 {
 		connectionInfo := map[string]interface{}{
-			"note": "Use kubectl or virtctl to connect to consoles",
+			"note": "Use kubectl or virtctl to connect to consoles, or the get_vm_serial_output tool to capture serial output",
 			"commands": map[string]string{
 				"vnc":    fmt.Sprintf("virtctl vnc %s -n %s", name, namespace),
 				"serial": fmt.Sprintf("virtctl console %s -n %s", name, namespace),
@@ -567,20 +541,105 @@ func VmGetConsole(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.R
 	}, nil
 }
 
+func VmSnapshotsList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri, err := vmSnapshotsTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	namespace := uri.Vars["namespace"]
+	name := uri.Vars["name"]
+
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := virtClient.VirtualMachineSnapshot(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Oldest first so the most recent snapshot is listed last
+	items := snapshots.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp)
+	})
+	snapshotList := make([]map[string]interface{}, 0, len(items))
+	for i := range items {
+		if items[i].Spec.Source.Name != name {
+			continue
+		}
+		snapshotList = append(snapshotList, output.SnapshotSummary(&items[i], nil))
+	}
+
+	snapshotsInfo := map[string]interface{}{
+		"vm":        name,
+		"namespace": namespace,
+		"snapshots": snapshotList,
+	}
+
+	jsonData, err := json.MarshalIndent(snapshotsInfo, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		&mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}, nil
+}
+
+func MigrationsList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri, err := migrationsTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	namespace := uri.Vars["namespace"]
+
+	page, err := pagination.FromQuery(uri.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := virtClient.VirtualMachineInstanceMigration(namespace).List(ctx, page.ListOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	migrationList := make([]map[string]interface{}, 0, len(migrations.Items))
+	for i := range migrations.Items {
+		migrationList = append(migrationList, output.MigrationSummary(&migrations.Items[i]))
+	}
+
+	return paginatedContents(request.Params.URI, page, migrations.ListMeta, migrationList)
+}
+
 func InstancetypesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace from URI: kubevirt://{namespace}/instancetypes
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 3 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/instancetypes")
+	uri, err := instancetypesTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	namespace := uri.Vars["namespace"]
+
+	page, err := pagination.FromQuery(uri.Query)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	instancetypes, err := virtClient.VirtualMachineInstancetype(namespace).List(ctx, metav1.ListOptions{})
+	instancetypes, err := virtClient.VirtualMachineInstancetype(namespace).List(ctx, page.ListOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -604,34 +663,27 @@ func InstancetypesList(ctx context.Context, request mcp.ReadResourceRequest) ([]
 		instancetypeList = append(instancetypeList, itInfo)
 	}
 
-	jsonData, err := json.MarshalIndent(instancetypeList, "", "  ")
+	return paginatedContents(request.Params.URI, page, instancetypes.ListMeta, instancetypeList)
+}
+
+func PreferencesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri, err := preferencesTemplate.Match(request.Params.URI)
 	if err != nil {
 		return nil, err
 	}
+	namespace := uri.Vars["namespace"]
 
-	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
-			MIMEType: "application/json",
-			Text:     string(jsonData),
-		},
-	}, nil
-}
-
-func PreferencesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse namespace from URI: kubevirt://{namespace}/preferences
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 3 {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://{namespace}/preferences")
+	page, err := pagination.FromQuery(uri.Query)
+	if err != nil {
+		return nil, err
 	}
-	namespace := parts[2]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	preferences, err := virtClient.VirtualMachinePreference(namespace).List(ctx, metav1.ListOptions{})
+	preferences, err := virtClient.VirtualMachinePreference(namespace).List(ctx, page.ListOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -652,25 +704,18 @@ func PreferencesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mc
 		preferenceList = append(preferenceList, prefInfo)
 	}
 
-	jsonData, err := json.MarshalIndent(preferenceList, "", "  ")
+	return paginatedContents(request.Params.URI, page, preferences.ListMeta, preferenceList)
+}
+
+func ClusterInstancetypesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri, err := clusterInstancetypesTemplate.Match(request.Params.URI)
 	if err != nil {
 		return nil, err
 	}
 
-	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
-			MIMEType: "application/json",
-			Text:     string(jsonData),
-		},
-	}, nil
-}
-
-func ClusterInstancetypesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse URI: kubevirt://cluster/instancetypes
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 3 || parts[2] != "cluster" {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://cluster/instancetypes")
+	page, err := pagination.FromQuery(uri.Query)
+	if err != nil {
+		return nil, err
 	}
 
 	virtClient, err := client.GetKubevirtClient()
@@ -678,7 +723,7 @@ func ClusterInstancetypesList(ctx context.Context, request mcp.ReadResourceReque
 		return nil, err
 	}
 
-	instancetypes, err := virtClient.VirtualMachineClusterInstancetype().List(ctx, metav1.ListOptions{})
+	instancetypes, err := virtClient.VirtualMachineClusterInstancetype().List(ctx, page.ListOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -701,25 +746,18 @@ func ClusterInstancetypesList(ctx context.Context, request mcp.ReadResourceReque
 		instancetypeList = append(instancetypeList, itInfo)
 	}
 
-	jsonData, err := json.MarshalIndent(instancetypeList, "", "  ")
+	return paginatedContents(request.Params.URI, page, instancetypes.ListMeta, instancetypeList)
+}
+
+func ClusterPreferencesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri, err := clusterPreferencesTemplate.Match(request.Params.URI)
 	if err != nil {
 		return nil, err
 	}
 
-	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
-			MIMEType: "application/json",
-			Text:     string(jsonData),
-		},
-	}, nil
-}
-
-func ClusterPreferencesList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse URI: kubevirt://cluster/preferences
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 3 || parts[2] != "cluster" {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://cluster/preferences")
+	page, err := pagination.FromQuery(uri.Query)
+	if err != nil {
+		return nil, err
 	}
 
 	virtClient, err := client.GetKubevirtClient()
@@ -727,7 +765,7 @@ func ClusterPreferencesList(ctx context.Context, request mcp.ReadResourceRequest
 		return nil, err
 	}
 
-	preferences, err := virtClient.VirtualMachineClusterPreference().List(ctx, metav1.ListOptions{})
+	preferences, err := virtClient.VirtualMachineClusterPreference().List(ctx, page.ListOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -747,59 +785,47 @@ func ClusterPreferencesList(ctx context.Context, request mcp.ReadResourceRequest
 		preferenceList = append(preferenceList, prefInfo)
 	}
 
-	jsonData, err := json.MarshalIndent(preferenceList, "", "  ")
-	if err != nil {
-		return nil, err
-	}
-
-	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
-			MIMEType: "application/json",
-			Text:     string(jsonData),
-		},
-	}, nil
+	return paginatedContents(request.Params.URI, page, preferences.ListMeta, preferenceList)
 }
 
 func ClusterInstancetypeGet(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse name from URI: kubevirt://cluster/instancetype/{name}
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 5 || parts[2] != "cluster" {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://cluster/instancetype/{name}")
+	uri, err := clusterInstancetypeTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
 	}
-	name := parts[4]
+	name := uri.Vars["name"]
 
-	virtClient, err := client.GetKubevirtClient()
+	opts, err := output.ParseOptions(uri.Query["format"], uri.Query["full"])
 	if err != nil {
 		return nil, err
 	}
 
-	instancetype, err := virtClient.VirtualMachineClusterInstancetype().Get(ctx, name, metav1.GetOptions{})
+	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	jsonData, err := json.MarshalIndent(instancetype, "", "  ")
+	instancetype, err := virtClient.VirtualMachineClusterInstancetype().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
-			MIMEType: "application/json",
-			Text:     string(jsonData),
-		},
-	}, nil
+	return renderedContents(request.Params.URI, instancetype, func() interface{} {
+		return output.InstancetypeSummary(instancetype.Name, instancetype.Spec)
+	}, opts)
 }
 
 func ClusterPreferenceGet(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Parse name from URI: kubevirt://cluster/preference/{name}
-	parts := strings.Split(request.Params.URI, "/")
-	if len(parts) < 5 || parts[2] != "cluster" {
-		return nil, fmt.Errorf("invalid URI format, expected kubevirt://cluster/preference/{name}")
+	uri, err := clusterPreferenceTemplate.Match(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	name := uri.Vars["name"]
+
+	opts, err := output.ParseOptions(uri.Query["format"], uri.Query["full"])
+	if err != nil {
+		return nil, err
 	}
-	name := parts[4]
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -811,16 +837,87 @@ func ClusterPreferenceGet(ctx context.Context, request mcp.ReadResourceRequest)
 		return nil, err
 	}
 
-	jsonData, err := json.MarshalIndent(preference, "", "  ")
+	return renderedContents(request.Params.URI, preference, func() interface{} {
+		return output.PreferenceSummary(preference.Name, preference.Spec)
+	}, opts)
+}
+
+// paginatedContents wraps a page of list items together with the cursor and
+// resource URI needed to fetch the rest of the list. Items already returned
+// by an earlier truncated response are skipped and trailing items are
+// dropped when the response would exceed the response budget.
+func paginatedContents(uri string, page pagination.Page, listMeta metav1.ListMeta, items []map[string]interface{}) ([]mcp.ResourceContents, error) {
+	items = pagination.Window(page, items)
+	returned, err := pagination.Fit(len(items), func(n int) (int, error) {
+		data, err := json.MarshalIndent(map[string]interface{}{"items": items[:n]}, "", "  ")
+		return len(data) + continuationOverhead, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	continuation := page.Continuation(listMeta, returned, len(items))
+
+	result := map[string]interface{}{
+		"items": items[:returned],
+	}
+	if err := addContinuation(result, uri, page, continuation); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return nil, err
 	}
 
 	return []mcp.ResourceContents{
 		&mcp.TextResourceContents{
-			URI:      request.Params.URI,
+			URI:      uri,
 			MIMEType: "application/json",
 			Text:     string(jsonData),
 		},
 	}, nil
 }
+
+// continuationOverhead reserves room within the response budget for the
+// cursor, next URI and truncation notice added alongside the items
+const continuationOverhead = 1024
+
+// addContinuation records the cursor and next resource URI of a partial list,
+// along with how many items were omitted when the response was truncated
+func addContinuation(result map[string]interface{}, uri string, page pagination.Page, continuation pagination.Continuation) error {
+	next, err := continuation.NextURI(uri, page)
+	if err != nil {
+		return err
+	}
+	if next == "" {
+		return nil
+	}
+	result["nextCursor"] = continuation.Cursor
+	result["next"] = next
+	if continuation.Remaining != nil {
+		result["remainingItemCount"] = *continuation.Remaining
+	}
+	if continuation.Omitted > 0 {
+		result["truncated"] = true
+		result["omitted"] = continuation.Omitted
+		result["message"] = fmt.Sprintf("%d items omitted to stay within the %d byte response budget, read %s to continue",
+			continuation.Omitted, pagination.MaxResponseBytes(), next)
+	}
+	return nil
+}
+
+// renderedContents encodes a single object in the requested output format
+func renderedContents(uri string, obj interface{}, summary func() interface{}, opts output.Options) ([]mcp.ResourceContents, error) {
+	text, mimeType, err := output.Render(obj, summary, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		&mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: mimeType,
+			Text:     text,
+		},
+	}, nil
+}
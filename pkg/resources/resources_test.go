@@ -2,6 +2,7 @@ package resources_test
 
 import (
 	"context"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	. "github.com/onsi/ginkgo/v2"
@@ -54,6 +55,23 @@ var _ = Describe("Resources", func() {
 				Expect(err.Error()).To(ContainSubstring("invalid URI format"))
 				Expect(result).To(BeNil())
 			})
+
+			It("should return an error for an invalid limit", func() {
+				request := mcp.ReadResourceRequest{
+					Params: struct {
+						URI       string                 `json:"uri"`
+						Arguments map[string]interface{} `json:"arguments,omitempty"`
+					}{
+						URI: "kubevirt://test-namespace/vms?limit=0",
+					},
+				}
+
+				result, err := resources.VmsList(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("limit must be between"))
+				Expect(result).To(BeNil())
+			})
 		})
 
 		Context("when given valid URI format", func() {
@@ -99,6 +117,47 @@ var _ = Describe("Resources", func() {
 		})
 	})
 
+	Describe("AllVmsList", func() {
+		Context("when given invalid URI", func() {
+			It("should return an error for a namespaced URI", func() {
+				request := mcp.ReadResourceRequest{
+					Params: struct {
+						URI       string                 `json:"uri"`
+						Arguments map[string]interface{} `json:"arguments,omitempty"`
+					}{
+						URI: "kubevirt://test-namespace/vms",
+					},
+				}
+
+				result, err := resources.AllVmsList(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid URI format"))
+				Expect(result).To(BeNil())
+			})
+		})
+
+		Context("when given valid URI format", func() {
+			It("should accept kubevirt://all/vms and attempt to list VMs", func() {
+				request := mcp.ReadResourceRequest{
+					Params: struct {
+						URI       string                 `json:"uri"`
+						Arguments map[string]interface{} `json:"arguments,omitempty"`
+					}{
+						URI: "kubevirt://all/vms",
+					},
+				}
+
+				// This will fail due to no KubeVirt cluster, but we're testing the URI parsing
+				result, err := resources.AllVmsList(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).NotTo(ContainSubstring("invalid URI format"))
+				Expect(result).To(BeNil())
+			})
+		})
+	})
+
 	Describe("VmGet", func() {
 		Context("when given invalid URI", func() {
 			It("should return an error for malformed URI", func() {
@@ -118,6 +177,23 @@ var _ = Describe("Resources", func() {
 				Expect(result).To(BeNil())
 			})
 
+			It("should return an error for an unsupported format", func() {
+				request := mcp.ReadResourceRequest{
+					Params: struct {
+						URI       string                 `json:"uri"`
+						Arguments map[string]interface{} `json:"arguments,omitempty"`
+					}{
+						URI: "kubevirt://test-namespace/vm/test-vm?format=xml",
+					},
+				}
+
+				result, err := resources.VmGet(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unsupported format"))
+				Expect(result).To(BeNil())
+			})
+
 			It("should return an error for URI missing VM name", func() {
 				request := mcp.ReadResourceRequest{
 					Params: struct {
@@ -515,8 +591,8 @@ var _ = Describe("Resources", func() {
 
 				Expect(err).To(HaveOccurred())
 				Expect(result).To(BeNil())
-				// Should not contain URI parsing errors
-				Expect(err.Error()).NotTo(ContainSubstring("invalid URI format"))
+				Expect(err.Error()).To(ContainSubstring("invalid URI format"))
+				Expect(err.Error()).To(ContainSubstring("name must not be empty"))
 			})
 		})
 
@@ -541,4 +617,68 @@ var _ = Describe("Resources", func() {
 			})
 		})
 	})
+
+	Describe("MigrationsList", func() {
+		It("should reject a URI with an invalid namespace", func() {
+			result, err := resources.MigrationsList(ctx, readRequest("kubevirt://Bad_NS/migrations"))
+
+			Expect(err).To(MatchError(ContainSubstring("invalid URI format")))
+			Expect(result).To(BeNil())
+		})
+
+		It("should be routed from the migrations URI", func() {
+			// This will fail due to no KubeVirt cluster, but we're testing the routing
+			result, err := resources.Route(ctx, readRequest("kubevirt://test-namespace/migrations?limit=10"))
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).NotTo(ContainSubstring("invalid URI format"))
+			Expect(result).To(BeNil())
+		})
+	})
+
+	Describe("VmSnapshotsList", func() {
+		It("should reject a URI without a virtual machine name", func() {
+			result, err := resources.VmSnapshotsList(ctx, readRequest("kubevirt://test-namespace/vm//snapshots"))
+
+			Expect(err).To(MatchError(ContainSubstring("name must not be empty")))
+			Expect(result).To(BeNil())
+		})
+
+		It("should be routed from the snapshots URI", func() {
+			// This will fail due to no KubeVirt cluster, but we're testing the routing
+			result, err := resources.Route(ctx, readRequest("kubevirt://test-namespace/vm/test-vm/snapshots"))
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).NotTo(ContainSubstring("invalid URI format"))
+			Expect(result).To(BeNil())
+		})
+	})
+
+	Describe("Templates", func() {
+		It("should advertise every routed template once", func() {
+			templates := resources.Templates()
+
+			uris := map[string]bool{}
+			for _, template := range templates {
+				uri := template.URITemplate.Raw()
+				Expect(uris).NotTo(HaveKey(uri))
+				uris[uri] = true
+				Expect(template.Name).NotTo(BeEmpty())
+				Expect(template.Description).NotTo(BeEmpty())
+			}
+			Expect(uris).To(HaveKey("kubevirt://{namespace}/vms{?limit,cursor}"))
+			Expect(uris).To(HaveKey("kubevirt://all/vms{?limit,cursor}"))
+			Expect(uris).To(HaveKey("kubevirt://cluster/preference/{name}{?format,full}"))
+		})
+
+		It("should leave the MIME type unset for templates accepting a format", func() {
+			for _, template := range resources.Templates() {
+				if strings.Contains(template.URITemplate.Raw(), "format") {
+					Expect(template.MIMEType).To(BeEmpty(), template.URITemplate.Raw())
+				} else {
+					Expect(template.MIMEType).To(Equal("application/json"), template.URITemplate.Raw())
+				}
+			}
+		})
+	})
 })
@@ -0,0 +1,110 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/output"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var (
+	vmsTemplate                  = MustParseTemplate("kubevirt://{namespace}/vms{?limit,cursor}")
+	allVmsTemplate               = MustParseTemplate("kubevirt://all/vms{?limit,cursor}")
+	vmTemplate                   = MustParseTemplate("kubevirt://{namespace}/vm/{name}{?format,full}")
+	vmisTemplate                 = MustParseTemplate("kubevirt://{namespace}/vmis{?limit,cursor}")
+	vmiTemplate                  = MustParseTemplate("kubevirt://{namespace}/vmi/{name}{?format,full}")
+	dataVolumesTemplate          = MustParseTemplate("kubevirt://{namespace}/datavolumes{?limit,cursor}")
+	dataVolumeTemplate           = MustParseTemplate("kubevirt://{namespace}/datavolume/{name}{?format,full}")
+	migrationsTemplate           = MustParseTemplate("kubevirt://{namespace}/migrations{?limit,cursor}")
+	vmStatusTemplate             = MustParseTemplate("kubevirt://{namespace}/vm/{name}/status")
+	vmiGuestOSInfoTemplate       = MustParseTemplate("kubevirt://{namespace}/vmi/{name}/guestosinfo")
+	vmiFilesystemsTemplate       = MustParseTemplate("kubevirt://{namespace}/vmi/{name}/filesystems")
+	vmiUserListTemplate          = MustParseTemplate("kubevirt://{namespace}/vmi/{name}/userlist")
+	vmSnapshotsTemplate          = MustParseTemplate("kubevirt://{namespace}/vm/{name}/snapshots")
+	vmConsoleTemplate            = MustParseTemplate("kubevirt://{namespace}/vm/{name}/console")
+	instancetypesTemplate        = MustParseTemplate("kubevirt://{namespace}/instancetypes{?limit,cursor}")
+	preferencesTemplate          = MustParseTemplate("kubevirt://{namespace}/preferences{?limit,cursor}")
+	clusterInstancetypesTemplate = MustParseTemplate("kubevirt://cluster/instancetypes{?limit,cursor}")
+	clusterPreferencesTemplate   = MustParseTemplate("kubevirt://cluster/preferences{?limit,cursor}")
+	clusterInstancetypeTemplate  = MustParseTemplate("kubevirt://cluster/instancetype/{name}{?format,full}")
+	clusterPreferenceTemplate    = MustParseTemplate("kubevirt://cluster/preference/{name}{?format,full}")
+)
+
+type route struct {
+	template *Template
+	handler  func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)
+
+	// name, description and mimeType are advertised when registering the
+	// template, resources accepting ?format= leave the MIME type unset as it
+	// depends on the requested format
+	name        string
+	description string
+	mimeType    string
+}
+
+// routes maps every kubevirt:// resource template to its handler. The MCP
+// server matches templates in no particular order, so overlapping templates
+// such as kubevirt://{namespace}/vms and kubevirt://all/vms are resolved here,
+// always in favour of the literal segment. Namespaces named all or cluster
+// are therefore only reachable through the tools.
+var routes []route
+
+func init() {
+	routes = []route{
+		{vmsTemplate, VmsList, "Virtual Machines", "List of virtual machines in a namespace", output.MIMETypeJSON},
+		{allVmsTemplate, AllVmsList, "All Virtual Machines", "Virtual machines across all readable namespaces grouped by namespace", output.MIMETypeJSON},
+		{vmTemplate, VmGet, "Virtual Machine", "Individual virtual machine details, ?format=yaml|summary selects the output format, yaml is served as application/yaml", ""},
+		{vmisTemplate, VmisList, "Virtual Machine Instances", "List of virtual machine instances in a namespace", output.MIMETypeJSON},
+		{vmiTemplate, VmiGet, "Virtual Machine Instance", "Individual virtual machine instance details, ?format=yaml|summary selects the output format, yaml is served as application/yaml", ""},
+		{dataVolumesTemplate, DataVolumesList, "Data Volumes", "List of data volumes with source and storage information", output.MIMETypeJSON},
+		{dataVolumeTemplate, DataVolumeGet, "Data Volume", "Individual data volume specification, ?format=yaml|summary selects the output format, yaml is served as application/yaml", ""},
+		{migrationsTemplate, MigrationsList, "Migrations", "List of virtual machine instance migrations with their phase and source and target nodes", output.MIMETypeJSON},
+		{vmStatusTemplate, VmGetStatus, "VM Status", "Virtual machine status and phase information", output.MIMETypeJSON},
+		{vmiGuestOSInfoTemplate, VmiGetGuestOSInfo, "VMI Guest OS Info", "Virtual machine instance guest operating system information", output.MIMETypeJSON},
+		{vmiFilesystemsTemplate, VmiGetFilesystems, "VMI Filesystems", "Virtual machine instance filesystem information", output.MIMETypeJSON},
+		{vmiUserListTemplate, VmiGetUserList, "VMI User List", "Virtual machine instance user list information", output.MIMETypeJSON},
+		{vmConsoleTemplate, VmGetConsole, "VM Console", "Virtual machine console connection details", output.MIMETypeJSON},
+		{vmSnapshotsTemplate, VmSnapshotsList, "VM Snapshots", "Snapshots of a virtual machine with their phase, readiness and indications", output.MIMETypeJSON},
+		{instancetypesTemplate, InstancetypesList, "Instance Types", "List of instance types in a namespace", output.MIMETypeJSON},
+		{preferencesTemplate, PreferencesList, "Preferences", "List of VM preferences in a namespace", output.MIMETypeJSON},
+		{clusterInstancetypesTemplate, ClusterInstancetypesList, "Cluster Instance Types", "List of cluster-wide instance types", output.MIMETypeJSON},
+		{clusterPreferencesTemplate, ClusterPreferencesList, "Cluster Preferences", "List of cluster-wide VM preferences", output.MIMETypeJSON},
+		{clusterInstancetypeTemplate, ClusterInstancetypeGet, "Cluster Instance Type", "Individual cluster instance type specification, ?format=yaml|summary selects the output format, yaml is served as application/yaml", ""},
+		{clusterPreferenceTemplate, ClusterPreferenceGet, "Cluster Preference", "Individual cluster preference specification, ?format=yaml|summary selects the output format, yaml is served as application/yaml", ""},
+	}
+}
+
+// Templates returns the MCP resource templates of every route, each to be
+// registered with Route as its handler
+func Templates() []mcp.ResourceTemplate {
+	templates := make([]mcp.ResourceTemplate, 0, len(routes))
+	for _, route := range routes {
+		options := []mcp.ResourceTemplateOption{mcp.WithTemplateDescription(route.description)}
+		if route.mimeType != "" {
+			options = append(options, mcp.WithTemplateMIMEType(route.mimeType))
+		}
+		templates = append(templates, mcp.NewResourceTemplate(route.template.String(), route.name, options...))
+	}
+	return templates
+}
+
+// Route dispatches a resource read to the handler of the most specific
+// template matching the URI. It is registered as the handler of every
+// kubevirt:// resource template.
+func Route(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	var best *route
+	for i := range routes {
+		candidate := &routes[i]
+		if !candidate.template.shapeMatches(request.Params.URI) {
+			continue
+		}
+		if best == nil || candidate.template.literals() > best.template.literals() {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("invalid URI format, no kubevirt resource matches %q", request.Params.URI)
+	}
+	return best.handler(ctx, request)
+}
@@ -0,0 +1,209 @@
+package resources
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const uriScheme = "kubevirt://"
+
+// Template is a parsed kubevirt:// URI template made of literal and
+// {variable} path segments followed by an optional {?query,variables} list.
+type Template struct {
+	raw      string
+	segments []templateSegment
+	query    []string
+}
+
+type templateSegment struct {
+	literal  string
+	variable string
+}
+
+// URI holds the decoded variables and query parameters of a URI that matched a template
+type URI struct {
+	Raw   string
+	Vars  map[string]string
+	Query map[string]string
+}
+
+// ParseTemplate parses an RFC 6570 style kubevirt:// URI template supporting
+// simple {variable} path segments and a trailing {?query} expression.
+func ParseTemplate(raw string) (*Template, error) {
+	if !strings.HasPrefix(raw, uriScheme) {
+		return nil, fmt.Errorf("template %q must start with %s", raw, uriScheme)
+	}
+	rest := strings.TrimPrefix(raw, uriScheme)
+
+	template := &Template{raw: raw}
+	if idx := strings.Index(rest, "{?"); idx != -1 {
+		if !strings.HasSuffix(rest, "}") {
+			return nil, fmt.Errorf("template %q has an unterminated query expression", raw)
+		}
+		template.query = strings.Split(rest[idx+2:len(rest)-1], ",")
+		rest = rest[:idx]
+	}
+
+	for _, segment := range strings.Split(rest, "/") {
+		switch {
+		case segment == "":
+			return nil, fmt.Errorf("template %q contains an empty segment", raw)
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			template.segments = append(template.segments, templateSegment{variable: segment[1 : len(segment)-1]})
+		case strings.ContainsAny(segment, "{}"):
+			return nil, fmt.Errorf("template %q segment %q mixes literals and variables", raw, segment)
+		default:
+			template.segments = append(template.segments, templateSegment{literal: segment})
+		}
+	}
+	return template, nil
+}
+
+// MustParseTemplate is like ParseTemplate but panics on invalid templates
+func MustParseTemplate(raw string) *Template {
+	template, err := ParseTemplate(raw)
+	if err != nil {
+		panic(err)
+	}
+	return template
+}
+
+// String returns the raw template
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Match decodes the given URI against the template, validating every path
+// variable as a Kubernetes name and every query parameter against the template
+func (t *Template) Match(raw string) (*URI, error) {
+	segments, rawQuery, err := splitURI(raw)
+	if err != nil {
+		return nil, t.errorf("%v", err)
+	}
+
+	if len(segments) < len(t.segments) {
+		missing := t.segments[len(segments)]
+		if missing.variable != "" {
+			return nil, t.errorf("missing {%s} segment", missing.variable)
+		}
+		return nil, t.errorf("missing %q segment", missing.literal)
+	}
+	if len(segments) > len(t.segments) {
+		return nil, t.errorf("unexpected trailing segments %q", strings.Join(segments[len(t.segments):], "/"))
+	}
+
+	uri := &URI{
+		Raw:   raw,
+		Vars:  map[string]string{},
+		Query: map[string]string{},
+	}
+	for i, segment := range t.segments {
+		value, err := url.PathUnescape(segments[i])
+		if err != nil {
+			return nil, t.errorf("invalid percent-encoding in segment %q: %v", segments[i], err)
+		}
+		if segment.literal != "" {
+			if value != segment.literal {
+				return nil, t.errorf("expected %q segment, got %q", segment.literal, value)
+			}
+			continue
+		}
+		if err := validateVariable(segment.variable, value); err != nil {
+			return nil, t.errorf("%v", err)
+		}
+		uri.Vars[segment.variable] = value
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, t.errorf("invalid query string %q: %v", rawQuery, err)
+	}
+	for key, values := range query {
+		if !t.supportsQuery(key) {
+			if len(t.query) == 0 {
+				return nil, t.errorf("unsupported query parameter %q, this resource takes no query parameters", key)
+			}
+			return nil, t.errorf("unsupported query parameter %q, supported parameters are %s", key, strings.Join(t.query, ", "))
+		}
+		if len(values) > 1 {
+			return nil, t.errorf("query parameter %q given more than once", key)
+		}
+		uri.Query[key] = values[0]
+	}
+
+	return uri, nil
+}
+
+// shapeMatches reports whether the URI has the same number of segments as the
+// template and agrees with every literal segment, without validating variables
+func (t *Template) shapeMatches(raw string) bool {
+	segments, _, err := splitURI(raw)
+	if err != nil || len(segments) != len(t.segments) {
+		return false
+	}
+	for i, segment := range t.segments {
+		if segment.literal == "" {
+			continue
+		}
+		if value, err := url.PathUnescape(segments[i]); err != nil || value != segment.literal {
+			return false
+		}
+	}
+	return true
+}
+
+// literals returns the number of literal segments, used to prefer specific
+// templates such as kubevirt://cluster/instancetypes over kubevirt://{namespace}/instancetypes
+func (t *Template) literals() int {
+	count := 0
+	for _, segment := range t.segments {
+		if segment.literal != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *Template) supportsQuery(key string) bool {
+	for _, supported := range t.query {
+		if supported == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Template) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid URI format, expected %s: %s", t.raw, fmt.Sprintf(format, args...))
+}
+
+func splitURI(raw string) ([]string, string, error) {
+	if !strings.HasPrefix(raw, uriScheme) {
+		return nil, "", fmt.Errorf("URI must start with %s", uriScheme)
+	}
+	rest := strings.TrimPrefix(raw, uriScheme)
+	if strings.Contains(rest, "#") {
+		return nil, "", fmt.Errorf("URI fragments are not supported")
+	}
+	path, rawQuery, _ := strings.Cut(rest, "?")
+	return strings.Split(path, "/"), rawQuery, nil
+}
+
+func validateVariable(variable, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s must not be empty", variable)
+	}
+	var errs []string
+	if variable == "namespace" {
+		errs = validation.IsDNS1123Label(value)
+	} else {
+		errs = validation.IsDNS1123Subdomain(value)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid %s %q: %s", variable, value, strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package resources_test
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/resources"
+)
+
+func readRequest(uri string) mcp.ReadResourceRequest {
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	return request
+}
+
+var _ = Describe("URI", func() {
+	Describe("ParseTemplate", func() {
+		It("should reject templates without the kubevirt scheme", func() {
+			_, err := resources.ParseTemplate("http://{namespace}/vms")
+			Expect(err).To(MatchError(ContainSubstring("must start with kubevirt://")))
+		})
+
+		It("should reject templates mixing literals and variables in a segment", func() {
+			_, err := resources.ParseTemplate("kubevirt://{namespace}/vm-{name}")
+			Expect(err).To(MatchError(ContainSubstring("mixes literals and variables")))
+		})
+	})
+
+	Describe("Template.Match", func() {
+		var template *resources.Template
+
+		BeforeEach(func() {
+			template = resources.MustParseTemplate("kubevirt://{namespace}/vm/{name}{?limit,cursor}")
+		})
+
+		It("should decode path variables and query parameters", func() {
+			uri, err := template.Match("kubevirt://test%2Dns/vm/my-vm?limit=5&cursor=abc%3D")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uri.Vars).To(Equal(map[string]string{"namespace": "test-ns", "name": "my-vm"}))
+			Expect(uri.Query).To(Equal(map[string]string{"limit": "5", "cursor": "abc="}))
+		})
+
+		It("should reject trailing segments", func() {
+			_, err := template.Match("kubevirt://test-ns/vm/my-vm/garbage")
+			Expect(err).To(MatchError(ContainSubstring(`unexpected trailing segments "garbage"`)))
+		})
+
+		It("should reject missing segments", func() {
+			_, err := template.Match("kubevirt://test-ns/vm")
+			Expect(err).To(MatchError(ContainSubstring("missing {name} segment")))
+		})
+
+		It("should reject mismatched literal segments", func() {
+			_, err := template.Match("kubevirt://test-ns/vmi/my-vm")
+			Expect(err).To(MatchError(ContainSubstring(`expected "vm" segment, got "vmi"`)))
+		})
+
+		It("should reject names that are not DNS-1123 compliant", func() {
+			_, err := template.Match("kubevirt://Test_NS/vm/my-vm")
+			Expect(err).To(MatchError(ContainSubstring(`invalid namespace "Test_NS"`)))
+		})
+
+		It("should keep query strings out of names", func() {
+			_, err := template.Match("kubevirt://test-ns/vm/my-vm?format=yaml")
+			Expect(err).To(MatchError(ContainSubstring(`unsupported query parameter "format"`)))
+		})
+
+		It("should reject repeated query parameters", func() {
+			_, err := template.Match("kubevirt://test-ns/vm/my-vm?limit=1&limit=2")
+			Expect(err).To(MatchError(ContainSubstring(`query parameter "limit" given more than once`)))
+		})
+	})
+
+	Describe("Route", func() {
+		It("should reject URIs matching no resource", func() {
+			result, err := resources.Route(context.Background(), readRequest("kubevirt://test-ns/vm/my-vm/garbage"))
+
+			Expect(err).To(MatchError(ContainSubstring("no kubevirt resource matches")))
+			Expect(result).To(BeNil())
+		})
+
+		It("should return precise validation errors from the matching template", func() {
+			result, err := resources.Route(context.Background(), readRequest("kubevirt://test-ns/vmi/Bad_Name"))
+
+			Expect(err).To(MatchError(ContainSubstring(`invalid name "Bad_Name"`)))
+			Expect(result).To(BeNil())
+		})
+
+		It("should route valid URIs to their handler", func() {
+			// This will fail due to no KubeVirt cluster, but we're testing the routing
+			result, err := resources.Route(context.Background(), readRequest("kubevirt://cluster/instancetypes?limit=10"))
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).NotTo(ContainSubstring("invalid URI format"))
+			Expect(result).To(BeNil())
+		})
+	})
+})
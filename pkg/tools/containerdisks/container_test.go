@@ -1,6 +1,8 @@
 package containerdisks_test
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -73,4 +75,28 @@ var _ = Describe("ContainerDisks", func() {
 			})
 		})
 	})
+
+	Describe("ImageLabels", func() {
+		Context("when given invalid image references", func() {
+			It("should return an error for an empty image", func() {
+				_, err := containerdisks.ImageLabels(context.Background(), "", "")
+				Expect(err).To(MatchError(ContainSubstring("empty image reference")))
+			})
+
+			It("should return an error for an image with an empty tag", func() {
+				_, err := containerdisks.ImageLabels(context.Background(), "quay.io/containerdisks/fedora:", "")
+				Expect(err).To(MatchError(ContainSubstring("invalid image reference")))
+			})
+		})
+	})
+
+	Describe("ResolveArchitecture", func() {
+		Context("when given invalid image references", func() {
+			It("should return an error for an empty image", func() {
+				_, err := containerdisks.ResolveArchitecture(context.Background(), "", "arm64")
+				Expect(err).To(MatchError(ContainSubstring("empty image reference")))
+				Expect(err).NotTo(MatchError(containerdisks.ErrArchitectureUnavailable))
+			})
+		})
+	})
 })
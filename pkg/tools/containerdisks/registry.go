@@ -0,0 +1,281 @@
+package containerdisks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	dockerHubRegistry   = "registry-1.docker.io"
+	registryTimeout     = 30 * time.Second
+	defaultArchitecture = "amd64"
+
+	mediaTypeOCIIndex          = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest       = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerList        = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest    = "application/vnd.docker.distribution.manifest.v2+json"
+	acceptedManifestMediaTypes = mediaTypeOCIIndex + ", " + mediaTypeDockerList + ", " + mediaTypeOCIManifest + ", " + mediaTypeDockerManifest
+)
+
+// imageReference is a container image split into the parts the registry API
+// addresses it by
+type imageReference struct {
+	registry   string
+	repository string
+	reference  string
+}
+
+type manifest struct {
+	MediaType string `json:"mediaType"`
+	Config    struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+type imageConfig struct {
+	Architecture string `json:"architecture"`
+	Config       struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+}
+
+// ErrArchitectureUnavailable is returned when an image is not built for the
+// requested architecture
+var ErrArchitectureUnavailable = errors.New("image is not available for the requested architecture")
+
+// ImageLabels reads the labels of a container disk image from its registry,
+// anonymously, picking the linux image of the given architecture from
+// multi-arch images. An empty architecture prefers amd64 and accepts any
+// single architecture image.
+func ImageLabels(ctx context.Context, image, architecture string) (map[string]string, error) {
+	config, err := platformConfig(ctx, image, architecture)
+	if err != nil {
+		return nil, err
+	}
+	return config.Config.Labels, nil
+}
+
+// ResolveArchitecture returns an image that runs on the given architecture,
+// the image itself when its manifest list or single manifest covers it and
+// otherwise an architecture suffixed tag such as fedora:41-arm64 when the
+// registry has one. ErrArchitectureUnavailable is wrapped when neither does.
+func ResolveArchitecture(ctx context.Context, image, architecture string) (string, error) {
+	_, err := platformConfig(ctx, image, architecture)
+	if err == nil || !errors.Is(err, ErrArchitectureUnavailable) {
+		return image, err
+	}
+	if tagged := architectureTag(image, architecture); tagged != "" {
+		if _, taggedErr := platformConfig(ctx, tagged, architecture); taggedErr == nil {
+			return tagged, nil
+		}
+	}
+	return "", err
+}
+
+// architectureTag appends the architecture to the tag of an image, images
+// pinned by digest have no such variant
+func architectureTag(image, architecture string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image + "-" + architecture
+	}
+	return image + ":latest-" + architecture
+}
+
+// platformConfig reads the config of the linux image of the given
+// architecture, following manifest lists to the matching entry
+func platformConfig(ctx context.Context, image, architecture string) (*imageConfig, error) {
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, registryTimeout)
+	defer cancel()
+	r := &registryClient{client: http.DefaultClient, ref: ref}
+
+	var m manifest
+	if err := r.getJSON(ctx, "manifests/"+ref.reference, acceptedManifestMediaTypes, &m); err != nil {
+		return nil, fmt.Errorf("unable to read manifest of image %s: %w", image, err)
+	}
+	if m.MediaType == mediaTypeOCIIndex || m.MediaType == mediaTypeDockerList || len(m.Manifests) > 0 {
+		wanted := architecture
+		if wanted == "" {
+			wanted = defaultArchitecture
+		}
+		digest := ""
+		for _, candidate := range m.Manifests {
+			if candidate.Platform.OS != "linux" {
+				continue
+			}
+			if candidate.Platform.Architecture == wanted {
+				digest = candidate.Digest
+				break
+			}
+			if digest == "" && architecture == "" {
+				digest = candidate.Digest
+			}
+		}
+		if digest == "" {
+			return nil, fmt.Errorf("%w: %s has no linux/%s variant", ErrArchitectureUnavailable, image, wanted)
+		}
+		m = manifest{}
+		if err := r.getJSON(ctx, "manifests/"+digest, acceptedManifestMediaTypes, &m); err != nil {
+			return nil, fmt.Errorf("unable to read linux/%s manifest of image %s: %w", wanted, image, err)
+		}
+	}
+	if m.Config.Digest == "" {
+		return nil, fmt.Errorf("manifest of image %s does not reference an image config", image)
+	}
+
+	config := &imageConfig{}
+	if err := r.getJSON(ctx, "blobs/"+m.Config.Digest, "", config); err != nil {
+		return nil, fmt.Errorf("unable to read config of image %s: %w", image, err)
+	}
+	if architecture != "" && config.Architecture != "" && config.Architecture != architecture {
+		return nil, fmt.Errorf("%w: %s is built for %s", ErrArchitectureUnavailable, image, config.Architecture)
+	}
+	return config, nil
+}
+
+// parseImageReference splits an image such as quay.io/containerdisks/fedora:41
+// or docker://ubuntu, applying the Docker Hub defaults for short names
+func parseImageReference(image string) (*imageReference, error) {
+	name := strings.TrimPrefix(strings.TrimSpace(image), "docker://")
+	if name == "" {
+		return nil, fmt.Errorf("empty image reference")
+	}
+
+	ref := &imageReference{registry: dockerHubRegistry, reference: "latest"}
+	if first, rest, found := strings.Cut(name, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.registry = first
+		name = rest
+	}
+	if digestName, digest, found := strings.Cut(name, "@"); found {
+		name, ref.reference = digestName, digest
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.reference = name[:i], name[i+1:]
+	}
+	if name == "" || ref.reference == "" {
+		return nil, fmt.Errorf("invalid image reference %q", image)
+	}
+	if ref.registry == dockerHubRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.repository = name
+	return ref, nil
+}
+
+// registryClient talks to the v2 registry API, fetching an anonymous bearer
+// token the first time the registry asks for one
+type registryClient struct {
+	client *http.Client
+	ref    *imageReference
+	token  string
+}
+
+func (r *registryClient) getJSON(ctx context.Context, path, accept string, into interface{}) error {
+	endpoint := fmt.Sprintf("https://%s/v2/%s/%s", r.ref.registry, r.ref.repository, path)
+	resp, err := r.get(ctx, endpoint, accept)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && r.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if r.token, err = r.fetchToken(ctx, challenge); err != nil {
+			return err
+		}
+		if resp, err = r.get(ctx, endpoint, accept); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry returned %s for %s", resp.Status, endpoint)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(into)
+}
+
+func (r *registryClient) get(ctx context.Context, endpoint, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	return r.client.Do(req)
+}
+
+// fetchToken answers a Bearer WWW-Authenticate challenge with an anonymous
+// token request, which is all public registries require to pull
+func (r *registryClient) fetchToken(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("registry %s requires %s authentication, only anonymous pulls are supported", r.ref.registry, scheme)
+	}
+	values := map[string]string{}
+	for _, param := range strings.Split(params, ",") {
+		if key, value, found := strings.Cut(strings.TrimSpace(param), "="); found {
+			values[key] = strings.Trim(value, `"`)
+		}
+	}
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("registry %s sent an invalid authentication realm %q", r.ref.registry, values["realm"])
+	}
+	query := realm.Query()
+	if values["service"] != "" {
+		query.Set("service", values["service"])
+	}
+	scope := values["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", r.ref.repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry %s refused an anonymous pull token: %s", r.ref.registry, resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
@@ -2,9 +2,10 @@ package instancetype
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/output"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/pagination"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,21 +29,26 @@ func List(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult
 		return newToolResultErr(err)
 	}
 
-	instancetypes, err := virtClient.VirtualMachineClusterInstancetype().List(ctx, metav1.ListOptions{})
+	page, err := pagination.FromToolRequest(request)
 	if err != nil {
 		return newToolResultErr(err)
 	}
 
-	names := ""
+	instancetypes, err := virtClient.VirtualMachineClusterInstancetype().List(ctx, page.ListOptions())
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	names := make([]string, 0, len(instancetypes.Items))
 	for _, instancetype := range instancetypes.Items {
-		names += fmt.Sprintf("%s\n", instancetype.Name)
+		names = append(names, instancetype.Name)
 	}
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: names,
+				Text: pagination.NameList(page, instancetypes.ListMeta, names),
 			},
 		},
 	}, nil
@@ -53,6 +59,10 @@ func Get(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult,
 	if err != nil {
 		return newToolResultErr(fmt.Errorf("name parameter is required: %w", err))
 	}
+	format, err := output.ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return newToolResultErr(err)
+	}
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -71,7 +81,9 @@ func Get(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult,
 		"spec":        instancetype.Spec,
 	}
 
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	text, _, err := output.Render(result, func() interface{} {
+		return output.InstancetypeSummary(instancetype.Name, instancetype.Spec)
+	}, output.Options{Format: format, Full: request.GetBool("full", false)})
 	if err != nil {
 		return newToolResultErr(err)
 	}
@@ -80,7 +92,7 @@ func Get(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult,
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: string(resultJSON),
+				Text: text,
 			},
 		},
 	}, nil
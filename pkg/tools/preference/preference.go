@@ -2,9 +2,9 @@ package preference
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/output"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,6 +27,10 @@ func Get(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult,
 	if err != nil {
 		return newToolResultErr(fmt.Errorf("name parameter is required: %w", err))
 	}
+	format, err := output.ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return newToolResultErr(err)
+	}
 
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -45,7 +49,9 @@ func Get(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult,
 		"spec":        preference.Spec,
 	}
 
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	text, _, err := output.Render(result, func() interface{} {
+		return output.PreferenceSummary(preference.Name, preference.Spec)
+	}, output.Options{Format: format, Full: request.GetBool("full", false)})
 	if err != nil {
 		return newToolResultErr(err)
 	}
@@ -54,7 +60,7 @@ func Get(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult,
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: string(resultJSON),
+				Text: text,
 			},
 		},
 	}, nil
@@ -43,6 +43,20 @@ var _ = Describe("Preference", func() {
 		})
 
 		Context("when called with invalid arguments", func() {
+			It("should reject an unsupported format", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name":   "test",
+					"format": "xml",
+				}
+
+				result, err := preference.Get(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported format"))
+			})
+
 			It("should reject missing name parameter", func() {
 				request := mcp.CallToolRequest{}
 				request.Params.Arguments = map[string]interface{}{}
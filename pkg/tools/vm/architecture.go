@@ -0,0 +1,48 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+// supportedArchitectures are the guest architectures KubeVirt can run
+var supportedArchitectures = []string{"amd64", "arm64", "s390x"}
+
+func validateArchitecture(architecture string) error {
+	if slices.Contains(supportedArchitectures, architecture) {
+		return nil
+	}
+	return fmt.Errorf("unsupported architecture %q, expected amd64, arm64 or s390x", architecture)
+}
+
+// requireArchitectureNodes checks that at least one schedulable KubeVirt node
+// of the architecture exists, as a virtual machine asking for another
+// architecture stays in Scheduling forever. The check is skipped when the
+// caller may not list nodes.
+func requireArchitectureNodes(ctx context.Context, virtClient kubecli.KubevirtClient, architecture string) error {
+	selector := labels.SelectorFromSet(labels.Set{
+		corev1.LabelArchStable: architecture,
+		virtv1.NodeSchedulable: "true",
+	})
+	nodes, err := virtClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if k8serrors.IsForbidden(err) || k8serrors.IsUnauthorized(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to list %s nodes: %w", architecture, err)
+	}
+	for _, node := range nodes.Items {
+		if !node.Spec.Unschedulable {
+			return nil
+		}
+	}
+	return fmt.Errorf("no schedulable KubeVirt nodes of architecture %s, the VM would never leave Scheduling", architecture)
+}
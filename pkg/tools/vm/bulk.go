@@ -0,0 +1,202 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"kubevirt.io/client-go/kubecli"
+)
+
+const (
+	// bulkConfirmThreshold is the number of virtual machines a bulk action
+	// may touch before it has to be confirmed
+	bulkConfirmThreshold   = 10
+	bulkDefaultConcurrency = 5
+	bulkMaxConcurrency     = 20
+
+	bulkSucceeded = "success"
+	bulkSkipped   = "skipped"
+	bulkFailed    = "error"
+)
+
+type toolHandler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+
+// bulkActions maps the actions of bulk_vm_action to the single virtual
+// machine tool handlers that perform them
+var bulkActions = map[string]toolHandler{
+	"start":   Start,
+	"stop":    Stop,
+	"restart": Restart,
+	"pause":   Pause,
+	"unpause": Unpause,
+	"delete":  Delete,
+}
+
+// bulkResult is the outcome of the action on one virtual machine
+type bulkResult struct {
+	Name    string `json:"name"`
+	Outcome string `json:"outcome"`
+	Message string `json:"message"`
+}
+
+func BulkAction(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	action, err := request.RequireString("action")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("action parameter required: %w", err))
+	}
+	if _, ok := bulkActions[action]; !ok {
+		return newToolResultErr(fmt.Errorf("unsupported action %q, expected start, stop, restart, pause, unpause or delete", action))
+	}
+	labelSelector := request.GetString("label_selector", "")
+	names := request.GetStringSlice("names", nil)
+	switch {
+	case labelSelector != "" && len(names) > 0:
+		return newToolResultErr(fmt.Errorf("label_selector and names parameters are mutually exclusive"))
+	case labelSelector == "" && len(names) == 0:
+		return newToolResultErr(fmt.Errorf("label_selector or names parameter required"))
+	}
+	if labelSelector != "" {
+		if _, err := labels.Parse(labelSelector); err != nil {
+			return newToolResultErr(fmt.Errorf("invalid label_selector %q: %w", labelSelector, err))
+		}
+	}
+	concurrency, err := parseBoundedInt(request.GetArguments()["concurrency"], "concurrency", bulkDefaultConcurrency, 1, bulkMaxConcurrency)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	targets, err := bulkTargets(ctx, virtClient, namespace, labelSelector, names)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if len(targets) == 0 {
+		return newToolResultText(fmt.Sprintf("no virtual machines match label selector %s in namespace %s", labelSelector, namespace))
+	}
+	if len(targets) > bulkConfirmThreshold && !request.GetBool("confirm", false) {
+		return newToolResultErr(fmt.Errorf("%s would affect %d virtual machines, more than %d, call again with confirm set to true to proceed: %s",
+			action, len(targets), bulkConfirmThreshold, strings.Join(targets, ", ")))
+	}
+
+	results := runBulkAction(ctx, virtClient, namespace, action, targets, concurrency)
+
+	summary := map[string]int{bulkSucceeded: 0, bulkSkipped: 0, bulkFailed: 0}
+	for _, result := range results {
+		summary[result.Outcome]++
+	}
+	response := map[string]interface{}{
+		"namespace": namespace,
+		"action":    action,
+		"total":     len(results),
+		"succeeded": summary[bulkSucceeded],
+		"skipped":   summary[bulkSkipped],
+		"failed":    summary[bulkFailed],
+		"results":   results,
+	}
+	if labelSelector != "" {
+		response["labelSelector"] = labelSelector
+	}
+	return newToolResultJSON(response)
+}
+
+// bulkTargets returns the sorted names of the virtual machines to act on,
+// listing them by label selector or deduplicating the explicit names
+func bulkTargets(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, labelSelector string, names []string) ([]string, error) {
+	if labelSelector == "" {
+		targets := slices.Clone(names)
+		slices.Sort(targets)
+		return slices.Compact(targets), nil
+	}
+	vms, err := virtClient.VirtualMachine(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list virtual machines matching %s: %w", labelSelector, err)
+	}
+	targets := make([]string, 0, len(vms.Items))
+	for _, vm := range vms.Items {
+		targets = append(targets, vm.Name)
+	}
+	slices.Sort(targets)
+	return targets, nil
+}
+
+// runBulkAction applies the action to every target through a pool of at most
+// concurrency workers, returning the results in the order of the targets
+func runBulkAction(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, action string, targets []string, concurrency int) []bulkResult {
+	results := make([]bulkResult, len(targets))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(targets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = bulkActionOn(ctx, virtClient, namespace, action, targets[i])
+			}
+		}()
+	}
+	for i := range targets {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// bulkActionOn runs the single virtual machine handler of the action, first
+// checking the power state so that virtual machines already in the requested
+// state are reported as skipped
+func bulkActionOn(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, action, name string) bulkResult {
+	result := bulkResult{Name: name}
+	switch lifecycleAction(action) {
+	case actionStart, actionStop, actionPause, actionUnpause:
+		message, err := lifecyclePreflight(ctx, virtClient, namespace, name, lifecycleAction(action))
+		if err != nil {
+			result.Outcome, result.Message = bulkFailed, err.Error()
+			return result
+		}
+		if message != "" {
+			result.Outcome, result.Message = bulkSkipped, message
+			return result
+		}
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"namespace": namespace,
+		"name":      name,
+	}
+	toolResult, err := bulkActions[action](ctx, request)
+	switch {
+	case toolResult != nil && toolResult.IsError:
+		result.Outcome, result.Message = bulkFailed, resultText(toolResult)
+	case err != nil:
+		result.Outcome, result.Message = bulkFailed, err.Error()
+	default:
+		result.Outcome, result.Message = bulkSucceeded, resultText(toolResult)
+	}
+	return result
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
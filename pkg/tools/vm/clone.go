@@ -0,0 +1,155 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/output"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clonev1beta1 "kubevirt.io/api/clone/v1beta1"
+	snapshotv1beta1 "kubevirt.io/api/snapshot/v1beta1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+const (
+	// clonePollInterval and cloneTimeout bound how long clone_vm waits for
+	// the clone to succeed
+	clonePollInterval = 2 * time.Second
+	cloneTimeout      = 10 * time.Minute
+)
+
+func Clone(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	source, err := cloneSource(request.GetString("name", ""), request.GetString("source_snapshot", ""))
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	targetName := request.GetString("target_name", "")
+	newMacAddresses, err := parseStringMap(request.GetArguments()["new_mac_addresses"], "new_mac_addresses")
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	waitForClone := request.GetBool("wait", true)
+
+	clone := &clonev1beta1.VirtualMachineClone{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: source.Name + "-clone-",
+			Namespace:    namespace,
+		},
+		Spec: clonev1beta1.VirtualMachineCloneSpec{
+			Source:            source,
+			LabelFilters:      request.GetStringSlice("label_filters", nil),
+			AnnotationFilters: request.GetStringSlice("annotation_filters", nil),
+			Template: clonev1beta1.VirtualMachineCloneTemplateFilters{
+				LabelFilters:      request.GetStringSlice("template_label_filters", nil),
+				AnnotationFilters: request.GetStringSlice("template_annotation_filters", nil),
+			},
+			NewMacAddresses: newMacAddresses,
+		},
+	}
+	if targetName != "" {
+		clone.Spec.Target = &corev1.TypedLocalObjectReference{
+			APIGroup: virtualMachineAPIGroup(),
+			Kind:     "VirtualMachine",
+			Name:     targetName,
+		}
+	}
+	if serial := request.GetString("new_smbios_serial", ""); serial != "" {
+		clone.Spec.NewSMBiosSerial = &serial
+	}
+
+	clone, err = virtClient.VirtualMachineClone(namespace).Create(ctx, clone, metav1.CreateOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	result := map[string]interface{}{
+		"clone":  clone.Name,
+		"source": fmt.Sprintf("%s/%s", source.Kind, source.Name),
+		"phase":  clone.Status.Phase,
+	}
+	if !waitForClone {
+		return newToolResultJSON(result)
+	}
+
+	clone, err = waitForCloneSucceeded(ctx, virtClient, clone)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	result["phase"] = clone.Status.Phase
+
+	if clone.Status.TargetName != nil {
+		vm, err := virtClient.VirtualMachine(namespace).Get(ctx, *clone.Status.TargetName, metav1.GetOptions{})
+		if err != nil {
+			return newToolResultErr(err)
+		}
+		result["vm"] = output.VirtualMachineSummary(vm)
+	}
+	return newToolResultJSON(result)
+}
+
+// cloneSource builds the clone source from either a virtual machine or a
+// virtual machine snapshot name
+func cloneSource(name, snapshotName string) (*corev1.TypedLocalObjectReference, error) {
+	switch {
+	case name != "" && snapshotName != "":
+		return nil, fmt.Errorf("name and source_snapshot parameters are mutually exclusive")
+	case name != "":
+		return &corev1.TypedLocalObjectReference{
+			APIGroup: virtualMachineAPIGroup(),
+			Kind:     "VirtualMachine",
+			Name:     name,
+		}, nil
+	case snapshotName != "":
+		group := snapshotv1beta1.SchemeGroupVersion.Group
+		return &corev1.TypedLocalObjectReference{
+			APIGroup: &group,
+			Kind:     "VirtualMachineSnapshot",
+			Name:     snapshotName,
+		}, nil
+	}
+	return nil, fmt.Errorf("name or source_snapshot parameter required")
+}
+
+func waitForCloneSucceeded(ctx context.Context, virtClient kubecli.KubevirtClient, clone *clonev1beta1.VirtualMachineClone) (*clonev1beta1.VirtualMachineClone, error) {
+	err := wait.PollUntilContextTimeout(ctx, clonePollInterval, cloneTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := virtClient.VirtualMachineClone(clone.Namespace).Get(ctx, clone.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		clone = current
+		switch current.Status.Phase {
+		case clonev1beta1.Succeeded:
+			return true, nil
+		case clonev1beta1.Failed:
+			return false, fmt.Errorf("clone %s failed%s", current.Name, cloneConditionMessage(current))
+		}
+		return false, nil
+	})
+	if wait.Interrupted(err) {
+		return nil, fmt.Errorf("timed out after %s waiting for clone %s, last phase %s", cloneTimeout, clone.Name, clone.Status.Phase)
+	}
+	return clone, err
+}
+
+func cloneConditionMessage(clone *clonev1beta1.VirtualMachineClone) string {
+	for _, cond := range clone.Status.Conditions {
+		if cond.Message != "" {
+			return ": " + cond.Message
+		}
+	}
+	return ""
+}
@@ -0,0 +1,187 @@
+package vm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	cloudInitDiskName = "cloudinitdisk"
+
+	// maxInlineCloudInitBytes is the largest inline user or network data
+	// KubeVirt accepts, bigger payloads have to come from a secret
+	maxInlineCloudInitBytes = 2048
+)
+
+// cloudInitConfig holds the cloud-init and SSH key arguments of create_vm
+type cloudInitConfig struct {
+	userData    string
+	networkData string
+	secretName  string
+	configDrive bool
+	sshKeys     []string
+}
+
+// parseCloudInit reads and validates the cloud-init arguments of a tool call,
+// returning nil when none are given
+func parseCloudInit(request mcp.CallToolRequest) (*cloudInitConfig, error) {
+	config := &cloudInitConfig{
+		userData:    request.GetString("user_data", ""),
+		networkData: request.GetString("network_data", ""),
+		secretName:  request.GetString("cloud_init_secret", ""),
+		sshKeys:     request.GetStringSlice("ssh_authorized_keys", nil),
+	}
+	switch source := request.GetString("cloud_init_datasource", "nocloud"); source {
+	case "nocloud":
+	case "configdrive":
+		config.configDrive = true
+	default:
+		return nil, fmt.Errorf("unsupported cloud_init_datasource %q, expected nocloud or configdrive", source)
+	}
+	if config.userData == "" && config.networkData == "" && config.secretName == "" && len(config.sshKeys) == 0 {
+		return nil, nil
+	}
+
+	if config.secretName != "" && (config.userData != "" || config.networkData != "") {
+		return nil, fmt.Errorf("cloud_init_secret is mutually exclusive with user_data and network_data")
+	}
+	if err := validateUserData(config.userData); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkData(config.networkData); err != nil {
+		return nil, err
+	}
+	for i, key := range config.sshKeys {
+		config.sshKeys[i] = strings.TrimSpace(key)
+		if len(strings.Fields(config.sshKeys[i])) < 2 {
+			return nil, fmt.Errorf("ssh_authorized_keys entry %d is not an SSH public key, expected e.g. \"ssh-ed25519 AAAA... user@host\"", i)
+		}
+	}
+	return config, nil
+}
+
+// validateUserData rejects user data that cloud-init would not understand,
+// parsing #cloud-config payloads as YAML
+func validateUserData(userData string) error {
+	if userData == "" {
+		return nil
+	}
+	if len(userData) > maxInlineCloudInitBytes {
+		return fmt.Errorf("user_data is %d bytes, KubeVirt accepts at most %d inline, store it in a secret and use cloud_init_secret", len(userData), maxInlineCloudInitBytes)
+	}
+	switch {
+	case strings.HasPrefix(userData, "#cloud-config"):
+		var config map[string]interface{}
+		if err := yaml.Unmarshal([]byte(userData), &config); err != nil {
+			return fmt.Errorf("user_data is not valid cloud-config YAML: %w", err)
+		}
+	case strings.HasPrefix(userData, "#!"), strings.HasPrefix(userData, "#include"), strings.HasPrefix(userData, "Content-Type:"):
+	default:
+		return fmt.Errorf("user_data must start with #cloud-config, a #! script shebang, #include or a MIME Content-Type header")
+	}
+	return nil
+}
+
+func validateNetworkData(networkData string) error {
+	if networkData == "" {
+		return nil
+	}
+	if len(networkData) > maxInlineCloudInitBytes {
+		return fmt.Errorf("network_data is %d bytes, KubeVirt accepts at most %d inline", len(networkData), maxInlineCloudInitBytes)
+	}
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(networkData), &config); err != nil {
+		return fmt.Errorf("network_data is not valid YAML: %w", err)
+	}
+	if _, ok := config["version"]; !ok {
+		return fmt.Errorf("network_data must set a network config version, e.g. \"version: 2\"")
+	}
+	return nil
+}
+
+// apply adds the cloud-init disk and volume and, when SSH keys are given,
+// the access credential propagating them through the same data source
+func (c *cloudInitConfig) apply(vm *virtv1.VirtualMachine) {
+	spec := &vm.Spec.Template.Spec
+	spec.Domain.Devices.Disks = append(spec.Domain.Devices.Disks, virtv1.Disk{
+		Name: cloudInitDiskName,
+		DiskDevice: virtv1.DiskDevice{
+			Disk: &virtv1.DiskTarget{
+				Bus: "virtio",
+			},
+		},
+	})
+
+	// SSH keys are merged into the user data by KubeVirt, which needs some
+	// user data to merge them into
+	userData := c.userData
+	if userData == "" && c.secretName == "" {
+		userData = "#cloud-config\n"
+	}
+	var secretRef *corev1.LocalObjectReference
+	if c.secretName != "" {
+		secretRef = &corev1.LocalObjectReference{Name: c.secretName}
+	}
+	volume := virtv1.Volume{Name: cloudInitDiskName}
+	if c.configDrive {
+		volume.CloudInitConfigDrive = &virtv1.CloudInitConfigDriveSource{
+			UserData:          userData,
+			UserDataSecretRef: secretRef,
+			NetworkData:       c.networkData,
+		}
+	} else {
+		volume.CloudInitNoCloud = &virtv1.CloudInitNoCloudSource{
+			UserData:          userData,
+			UserDataSecretRef: secretRef,
+			NetworkData:       c.networkData,
+		}
+	}
+	spec.Volumes = append(spec.Volumes, volume)
+
+	if len(c.sshKeys) == 0 {
+		return
+	}
+	propagation := virtv1.SSHPublicKeyAccessCredentialPropagationMethod{}
+	if c.configDrive {
+		propagation.ConfigDrive = &virtv1.ConfigDriveSSHPublicKeyAccessCredentialPropagation{}
+	} else {
+		propagation.NoCloud = &virtv1.NoCloudSSHPublicKeyAccessCredentialPropagation{}
+	}
+	spec.AccessCredentials = append(spec.AccessCredentials, virtv1.AccessCredential{
+		SSHPublicKey: &virtv1.SSHPublicKeyAccessCredential{
+			Source: virtv1.SSHPublicKeyAccessCredentialSource{
+				Secret: &virtv1.AccessCredentialSecretSource{SecretName: sshKeySecretName(vm.Name)},
+			},
+			PropagationMethod: propagation,
+		},
+	})
+}
+
+// sshKeySecret holds the authorized keys of a virtual machine, owned by it so
+// that it is removed together with the virtual machine
+func (c *cloudInitConfig) sshKeySecret(vm *virtv1.VirtualMachine) *corev1.Secret {
+	data := make(map[string]string, len(c.sshKeys))
+	for i, key := range c.sshKeys {
+		data[fmt.Sprintf("key%d", i+1)] = key
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sshKeySecretName(vm.Name),
+			Namespace: vm.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(vm, virtv1.VirtualMachineGroupVersionKind),
+			},
+		},
+		StringData: data,
+	}
+}
+
+func sshKeySecretName(vmName string) string {
+	return vmName + "-ssh-keys"
+}
@@ -1,7 +1,16 @@
 package vm
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/output"
 	"github.com/mark3labs/mcp-go/mcp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
 )
 
 func newToolResultErr(err error) (*mcp.CallToolResult, error) {
@@ -15,3 +24,116 @@ func newToolResultErr(err error) (*mcp.CallToolResult, error) {
 		},
 	}, err
 }
+
+// newToolResultText returns a plain text result
+func newToolResultText(text string) (*mcp.CallToolResult, error) {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}, nil
+}
+
+// newToolResultJSON returns the result encoded as indented JSON text
+func newToolResultJSON(result interface{}) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(resultJSON),
+			},
+		},
+	}, nil
+}
+
+// newToolResultFormatted returns the result encoded in the json or yaml format
+// requested through the format argument of the tool. The results of the VM
+// get tools are already condensed so summary renders the same as json.
+func newToolResultFormatted(result interface{}, format output.Format) (*mcp.CallToolResult, error) {
+	text, _, err := output.Render(result, func() interface{} { return result }, output.Options{Format: format, Full: true})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	return newToolResultText(text)
+}
+
+// kubevirtConfiguration returns the configuration of the KubeVirt
+// installation, or nil when the caller may not read it so that checks built on
+// it are skipped, leaving the API server to reject the request.
+func kubevirtConfiguration(ctx context.Context, virtClient kubecli.KubevirtClient) (*virtv1.KubeVirtConfiguration, error) {
+	kvs, err := virtClient.KubeVirt(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if k8serrors.IsForbidden(err) || k8serrors.IsUnauthorized(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the KubeVirt configuration: %w", err)
+	}
+	if len(kvs.Items) == 0 {
+		return nil, fmt.Errorf("no KubeVirt installation found")
+	}
+	return &kvs.Items[0].Spec.Configuration, nil
+}
+
+// requireFeatureGate fails when the KubeVirt installation does not enable the
+// given feature gate
+func requireFeatureGate(ctx context.Context, virtClient kubecli.KubevirtClient, gate string) error {
+	config, err := kubevirtConfiguration(ctx, virtClient)
+	if err != nil || config == nil {
+		return err
+	}
+	if config.DeveloperConfiguration != nil {
+		for _, enabled := range config.DeveloperConfiguration.FeatureGates {
+			if enabled == gate {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("the %s feature gate is not enabled in the KubeVirt configuration", gate)
+}
+
+// parseStringMap validates an optional object argument whose values are all strings
+func parseStringMap(raw interface{}, param string) (map[string]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	values, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s parameter must be an object of strings", param)
+	}
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s key %q must have a string value", param, key)
+		}
+		result[key] = str
+	}
+	return result, nil
+}
+
+// virtualMachineAPIGroup returns the API group used to reference virtual machines
+func virtualMachineAPIGroup() *string {
+	group := virtv1.GroupVersion.Group
+	return &group
+}
+
+// parseBoundedInt reads an optional whole number argument, which arrives as
+// a float64, returning def when it is not given
+func parseBoundedInt(raw interface{}, name string, def, lowest, highest int) (int, error) {
+	if raw == nil {
+		return def, nil
+	}
+	value, ok := raw.(float64)
+	if !ok || value != float64(int(value)) || value < float64(lowest) || value > float64(highest) {
+		return 0, fmt.Errorf("%s must be a whole number between %d and %d, got %v", name, lowest, highest, raw)
+	}
+	return int(value), nil
+}
@@ -2,7 +2,9 @@ package vm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/tools/containerdisks"
@@ -27,13 +29,45 @@ func Create(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResu
 	if err != nil {
 		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
 	}
-	containerDiskInput, err := request.RequireString("container_disk")
+	containerDiskInput := request.GetString("container_disk", "")
+	rootDisk, err := parseRootDisk(request, namespace)
 	if err != nil {
-		return newToolResultErr(fmt.Errorf("container_disk parameter required: %w", err))
+		return newToolResultErr(err)
+	}
+	switch {
+	case containerDiskInput != "" && rootDisk != nil:
+		return newToolResultErr(fmt.Errorf("container_disk is mutually exclusive with data_source and disk_image_url"))
+	case containerDiskInput == "" && rootDisk == nil:
+		return newToolResultErr(fmt.Errorf("container_disk parameter required, or data_source or disk_image_url for a persistent root disk"))
+	}
+	matchers, err := parseMatchers(request)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	cloudInit, err := parseCloudInit(request)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	network, err := parseNetworkConfig(request)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	architecture := request.GetString("architecture", "")
+	if architecture != "" {
+		if err := validateArchitecture(architecture); err != nil {
+			return newToolResultErr(err)
+		}
+	}
+	if network != nil {
+		if err := network.validate(ctx, virtClient, namespace); err != nil {
+			return newToolResultErr(err)
+		}
+	}
+	if architecture != "" {
+		if err := requireArchitectureNodes(ctx, virtClient, architecture); err != nil {
+			return newToolResultErr(err)
+		}
 	}
-
-	// Resolve the container disk image (handles OS names like "fedora", "ubuntu", etc.)
-	containerDisk := containerdisks.ResolveContainerDisk(containerDiskInput)
 
 	vm := &virtv1.VirtualMachine{
 		ObjectMeta: metav1.ObjectMeta{
@@ -44,58 +78,84 @@ func Create(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResu
 			RunStrategy: &[]virtv1.VirtualMachineRunStrategy{virtv1.RunStrategyHalted}[0],
 			Template: &virtv1.VirtualMachineInstanceTemplateSpec{
 				Spec: virtv1.VirtualMachineInstanceSpec{
-					Domain: virtv1.DomainSpec{
-						Devices: virtv1.Devices{
-							Disks: []virtv1.Disk{
-								{
-									Name: "containerdisk",
-									DiskDevice: virtv1.DiskDevice{
-										Disk: &virtv1.DiskTarget{
-											Bus: "virtio",
-										},
-									},
-								},
-							},
-						},
-					},
-					Volumes: []virtv1.Volume{
-						{
-							Name: "containerdisk",
-							VolumeSource: virtv1.VolumeSource{
-								ContainerDisk: &virtv1.ContainerDiskSource{
-									Image: containerDisk,
-								},
-							},
-						},
-					},
+					Architecture: architecture,
 				},
 			},
 		},
 	}
+	var imageNote string
 
-	// Only set memory resources if no instancetype is provided
-	// Instancetypes define their own resource requirements
-	hasInstancetype := false
-
-	instancetype := request.GetString("instancetype", "")
-	if instancetype != "" {
-		vm.Spec.Instancetype = &virtv1.InstancetypeMatcher{
-			Name: instancetype,
-			Kind: "VirtualMachineClusterInstancetype",
+	if rootDisk != nil {
+		dataSource, err := rootDisk.dataSource(ctx, virtClient)
+		if err != nil {
+			return newToolResultErr(err)
 		}
-		hasInstancetype = true
-	}
-
-	preference := request.GetString("preference", "")
-	if preference != "" {
-		vm.Spec.Preference = &virtv1.PreferenceMatcher{
-			Name: preference,
-			Kind: "VirtualMachineClusterPreference",
+		switch {
+		case dataSource != nil:
+			// DataSources of common images advertise the instance type and
+			// preference they are meant to be used with, KubeVirt reads the
+			// labels itself when asked to infer from the cloned volume
+			if matchers.inferring() {
+				matchers.inferFromVolume = rootDiskName
+			}
+			if err := matchers.fromLabels(dataSource.Labels, fmt.Sprintf("data source %s/%s", dataSource.Namespace, dataSource.Name)); err != nil {
+				return newToolResultErr(err)
+			}
+		case matchers.inferring() && strings.HasPrefix(rootDisk.imageURL, "docker://"):
+			if err := matchersFromImage(ctx, matchers, rootDisk.imageURL, architecture); err != nil {
+				return newToolResultErr(err)
+			}
+		case matchers.inferring():
+			return newToolResultErr(fmt.Errorf("cannot infer instance type or preference from %s, use a data_source, a docker:// disk_image_url or a container_disk", rootDisk.imageURL))
+		}
+		rootDisk.apply(vm)
+	} else {
+		// Resolve the container disk image (handles OS names like "fedora", "ubuntu", etc.)
+		containerDisk := containerdisks.ResolveContainerDisk(containerDiskInput)
+		if architecture != "" {
+			resolved, err := containerdisks.ResolveArchitecture(ctx, containerDisk, architecture)
+			switch {
+			case errors.Is(err, containerdisks.ErrArchitectureUnavailable):
+				return newToolResultErr(err)
+			case err != nil:
+				// Private registries cannot be checked anonymously, leave
+				// the image as given rather than refusing it
+				imageNote = fmt.Sprintf(", the %s variant of image %s could not be verified: %v", architecture, containerDisk, err)
+			default:
+				containerDisk = resolved
+			}
 		}
+		if matchers.inferring() {
+			// KubeVirt cannot infer from container disks, their images carry
+			// the default labels in their config instead
+			if err := matchersFromImage(ctx, matchers, containerDisk, architecture); err != nil {
+				return newToolResultErr(err)
+			}
+		}
+		template := &vm.Spec.Template.Spec
+		template.Domain.Devices.Disks = append(template.Domain.Devices.Disks, virtv1.Disk{
+			Name: "containerdisk",
+			DiskDevice: virtv1.DiskDevice{
+				Disk: &virtv1.DiskTarget{
+					Bus: "virtio",
+				},
+			},
+		})
+		template.Volumes = append(template.Volumes, virtv1.Volume{
+			Name: "containerdisk",
+			VolumeSource: virtv1.VolumeSource{
+				ContainerDisk: &virtv1.ContainerDiskSource{
+					Image: containerDisk,
+				},
+			},
+		})
 	}
 
+	matchers.apply(ctx, virtClient, vm)
+
 	// Set default memory only if no instancetype is provided
-	if !hasInstancetype {
+	// Instancetypes define their own resource requirements
+	if vm.Spec.Instancetype == nil {
 		vm.Spec.Template.Spec.Domain.Resources = virtv1.ResourceRequirements{
 			Requests: corev1.ResourceList{
 				corev1.ResourceMemory: resource.MustParse("128Mi"),
@@ -103,16 +163,56 @@ func Create(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResu
 		}
 	}
 
-	_, err = virtClient.VirtualMachine(namespace).Create(ctx, vm, metav1.CreateOptions{})
+	if cloudInit != nil {
+		cloudInit.apply(vm)
+	}
+	if network != nil {
+		network.apply(vm)
+	}
+
+	vm, err = virtClient.VirtualMachine(namespace).Create(ctx, vm, metav1.CreateOptions{})
 	if err != nil {
 		return newToolResultErr(err)
 	}
 
+	message := fmt.Sprintf("created VM %s in namespace %s", name, namespace)
+	if rootDisk != nil {
+		message += ", " + rootDisk.describe(vm.Name)
+	}
+	message += describeMatchers(vm)
+	if architecture != "" {
+		message += fmt.Sprintf(", architecture %s", architecture)
+	}
+	message += imageNote
+	if cloudInit != nil && len(cloudInit.sshKeys) > 0 {
+		secret := cloudInit.sshKeySecret(vm)
+		if _, err := virtClient.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			// The VM cannot start without its access credential secret
+			if deleteErr := virtClient.VirtualMachine(namespace).Delete(ctx, name, metav1.DeleteOptions{}); deleteErr != nil {
+				return newToolResultErr(fmt.Errorf("unable to create SSH key secret %s: %w, and unable to remove VM %s: %v", secret.Name, err, name, deleteErr))
+			}
+			return newToolResultErr(fmt.Errorf("unable to create SSH key secret %s, VM %s was removed: %w", secret.Name, name, err))
+		}
+		message += fmt.Sprintf(", SSH keys stored in secret %s", secret.Name)
+	}
+	if network != nil && len(network.exposePorts) > 0 {
+		service, err := virtClient.CoreV1().Services(namespace).Create(ctx, network.service(vm), metav1.CreateOptions{})
+		if err != nil {
+			// Remove the VM rather than leave it unreachable on the requested
+			// ports, the SSH key secret it owns is garbage collected with it
+			if deleteErr := virtClient.VirtualMachine(namespace).Delete(ctx, name, metav1.DeleteOptions{}); deleteErr != nil {
+				return newToolResultErr(fmt.Errorf("unable to create service for VM %s: %w, and unable to remove VM %s: %v", name, err, name, deleteErr))
+			}
+			return newToolResultErr(fmt.Errorf("unable to create service for VM %s, VM %s was removed: %w", name, name, err))
+		}
+		message += fmt.Sprintf(", exposed by %s service %s on ports %v", service.Spec.Type, service.Name, network.exposePorts)
+	}
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: fmt.Sprintf("created VM %s in namespace %s", name, namespace),
+				Text: message,
 			},
 		},
 	}, nil
@@ -0,0 +1,200 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+const (
+	podNetworkName = "default"
+
+	// vmNameLabel is set on the VMI template so that services can select the
+	// virt-launcher pod of the virtual machine
+	vmNameLabel = "vm.kubevirt.io/name"
+)
+
+// networkConfig holds the networking arguments of create_vm
+type networkConfig struct {
+	podBinding     string
+	networks       []string
+	macAddresses   map[string]string
+	interfaceModel string
+	exposePorts    []int32
+	serviceType    corev1.ServiceType
+}
+
+// parseNetworkConfig reads and validates the networking arguments of a tool
+// call, returning nil when the virtual machine keeps the implicit pod network
+func parseNetworkConfig(request mcp.CallToolRequest) (*networkConfig, error) {
+	args := request.GetArguments()
+	config := &networkConfig{
+		podBinding:     request.GetString("pod_network_binding", ""),
+		networks:       request.GetStringSlice("networks", nil),
+		interfaceModel: request.GetString("interface_model", ""),
+		serviceType:    corev1.ServiceType(request.GetString("service_type", string(corev1.ServiceTypeClusterIP))),
+	}
+	macAddresses, err := parseStringMap(args["mac_addresses"], "mac_addresses")
+	if err != nil {
+		return nil, err
+	}
+	config.macAddresses = macAddresses
+	if config.exposePorts, err = parsePorts(args["expose_ports"]); err != nil {
+		return nil, err
+	}
+	if config.podBinding == "" && len(config.networks) == 0 && len(config.macAddresses) == 0 && config.interfaceModel == "" && len(config.exposePorts) == 0 {
+		return nil, nil
+	}
+
+	switch config.podBinding {
+	case "":
+		config.podBinding = "masquerade"
+	case "masquerade", "bridge", "none":
+	default:
+		return nil, fmt.Errorf("unsupported pod_network_binding %q, expected masquerade, bridge or none", config.podBinding)
+	}
+	switch config.interfaceModel {
+	case "", virtv1.VirtIO, "e1000", "e1000e", "igb", "ne2k_pci", "pcnet", "rtl8139":
+	default:
+		return nil, fmt.Errorf("unsupported interface_model %q, expected virtio, e1000, e1000e, igb, ne2k_pci, pcnet or rtl8139", config.interfaceModel)
+	}
+	switch config.serviceType {
+	case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+	default:
+		return nil, fmt.Errorf("unsupported service_type %q, expected ClusterIP, NodePort or LoadBalancer", config.serviceType)
+	}
+	if config.podBinding == "none" && len(config.networks) == 0 {
+		return nil, fmt.Errorf("pod_network_binding none requires at least one network")
+	}
+	if config.podBinding == "none" && len(config.exposePorts) > 0 {
+		return nil, fmt.Errorf("expose_ports requires the pod network, set pod_network_binding to masquerade or bridge")
+	}
+
+	names := config.interfaceNames()
+	for iface, mac := range config.macAddresses {
+		if !slices.Contains(names, iface) {
+			return nil, fmt.Errorf("mac_addresses key %q does not match any interface, expected one of %v", iface, names)
+		}
+		if _, err := net.ParseMAC(mac); err != nil {
+			return nil, fmt.Errorf("mac_addresses value %q of interface %s is not a valid MAC address", mac, iface)
+		}
+	}
+	return config, nil
+}
+
+func parsePorts(raw interface{}) ([]int32, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	values, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expose_ports parameter must be an array of port numbers")
+	}
+	ports := make([]int32, 0, len(values))
+	for _, value := range values {
+		port, ok := value.(float64)
+		if !ok || port != float64(int32(port)) || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("expose_ports entry %v is not a port number between 1 and 65535", value)
+		}
+		ports = append(ports, int32(port))
+	}
+	return ports, nil
+}
+
+// interfaceNames lists the interfaces in the order they are attached, the pod
+// network first followed by net1, net2, ... for every secondary network
+func (c *networkConfig) interfaceNames() []string {
+	var names []string
+	if c.podBinding != "none" {
+		names = append(names, podNetworkName)
+	}
+	for i := range c.networks {
+		names = append(names, fmt.Sprintf("net%d", i+1))
+	}
+	return names
+}
+
+// validate checks that every secondary network exists
+func (c *networkConfig) validate(ctx context.Context, virtClient kubecli.KubevirtClient, namespace string) error {
+	for _, network := range c.networks {
+		if err := requireNetworkAttachmentDefinition(ctx, virtClient, namespace, network); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply adds the interfaces and their paired networks to the virtual machine
+func (c *networkConfig) apply(vm *virtv1.VirtualMachine) {
+	spec := &vm.Spec.Template.Spec
+	if c.podBinding != "none" {
+		binding := virtv1.InterfaceBindingMethod{Masquerade: &virtv1.InterfaceMasquerade{}}
+		if c.podBinding == "bridge" {
+			binding = virtv1.InterfaceBindingMethod{Bridge: &virtv1.InterfaceBridge{}}
+		}
+		spec.Domain.Devices.Interfaces = append(spec.Domain.Devices.Interfaces, c.iface(podNetworkName, binding))
+		spec.Networks = append(spec.Networks, *virtv1.DefaultPodNetwork())
+	}
+	for i, network := range c.networks {
+		name := fmt.Sprintf("net%d", i+1)
+		spec.Domain.Devices.Interfaces = append(spec.Domain.Devices.Interfaces, c.iface(name, virtv1.InterfaceBindingMethod{Bridge: &virtv1.InterfaceBridge{}}))
+		spec.Networks = append(spec.Networks, virtv1.Network{
+			Name: name,
+			NetworkSource: virtv1.NetworkSource{
+				Multus: &virtv1.MultusNetwork{NetworkName: network},
+			},
+		})
+	}
+
+	if len(c.exposePorts) > 0 {
+		if vm.Spec.Template.ObjectMeta.Labels == nil {
+			vm.Spec.Template.ObjectMeta.Labels = map[string]string{}
+		}
+		vm.Spec.Template.ObjectMeta.Labels[vmNameLabel] = vm.Name
+	}
+}
+
+func (c *networkConfig) iface(name string, binding virtv1.InterfaceBindingMethod) virtv1.Interface {
+	return virtv1.Interface{
+		Name:                   name,
+		InterfaceBindingMethod: binding,
+		Model:                  c.interfaceModel,
+		MacAddress:             c.macAddresses[name],
+	}
+}
+
+// service exposes the chosen ports of the virtual machine, owned by it so
+// that it is removed together with the virtual machine
+func (c *networkConfig) service(vm *virtv1.VirtualMachine) *corev1.Service {
+	ports := make([]corev1.ServicePort, 0, len(c.exposePorts))
+	for _, port := range c.exposePorts {
+		ports = append(ports, corev1.ServicePort{
+			Name:       fmt.Sprintf("port-%d", port),
+			Protocol:   corev1.ProtocolTCP,
+			Port:       port,
+			TargetPort: intstr.FromInt32(port),
+		})
+	}
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vm.Name,
+			Namespace: vm.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(vm, virtv1.VirtualMachineGroupVersionKind),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     c.serviceType,
+			Selector: map[string]string{vmNameLabel: vm.Name},
+			Ports:    ports,
+		},
+	}
+}
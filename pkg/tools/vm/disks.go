@@ -3,14 +3,60 @@ package vm
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
 	"github.com/mark3labs/mcp-go/mcp"
 
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
 )
 
+// diskInfo describes a disk of a virtual machine together with the volume
+// backing it and, for running virtual machines, its runtime status
+type diskInfo struct {
+	Name       string               `json:"name"`
+	Device     string               `json:"device"`
+	Bus        virtv1.DiskBus       `json:"bus,omitempty"`
+	BootOrder  *uint                `json:"bootOrder,omitempty"`
+	ReadOnly   bool                 `json:"readOnly,omitempty"`
+	Hotplugged bool                 `json:"hotplugged,omitempty"`
+	Persisted  bool                 `json:"persisted"`
+	Source     *volumeSource        `json:"source,omitempty"`
+	Claim      *claimInfo           `json:"persistentVolumeClaim,omitempty"`
+	DataVolume *dataVolumeInfo      `json:"dataVolume,omitempty"`
+	Status     *virtv1.VolumeStatus `json:"status,omitempty"`
+	Warnings   []string             `json:"warnings,omitempty"`
+}
+
+type volumeSource struct {
+	Type       string `json:"type"`
+	Image      string `json:"image,omitempty"`
+	ClaimName  string `json:"claimName,omitempty"`
+	DataVolume string `json:"dataVolume,omitempty"`
+	Secret     string `json:"secret,omitempty"`
+	ConfigMap  string `json:"configMap,omitempty"`
+}
+
+type claimInfo struct {
+	Name         string                              `json:"name"`
+	Phase        corev1.PersistentVolumeClaimPhase   `json:"phase"`
+	Capacity     *resource.Quantity                  `json:"capacity,omitempty"`
+	Requested    *resource.Quantity                  `json:"requested,omitempty"`
+	StorageClass string                              `json:"storageClass,omitempty"`
+	AccessModes  []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	VolumeMode   *corev1.PersistentVolumeMode        `json:"volumeMode,omitempty"`
+}
+
+type dataVolumeInfo struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Progress string `json:"progress,omitempty"`
+}
+
 func Disks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	virtClient, err := client.GetKubevirtClient()
 	if err != nil {
@@ -30,23 +76,197 @@ func Disks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResul
 	if err != nil {
 		return newToolResultErr(err)
 	}
+	if vm.Spec.Template == nil {
+		return newToolResultErr(fmt.Errorf("virtual machine %s has no template", name))
+	}
+	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		vmi = nil
+	} else if err != nil {
+		return newToolResultErr(err)
+	}
+
+	disks := describeDisks(vm, vmi)
+	for i := range disks {
+		addStorageDetails(ctx, virtClient, namespace, &disks[i])
+	}
+
+	return newToolResultJSON(map[string]interface{}{
+		"namespace": namespace,
+		"vm":        name,
+		"running":   vmi != nil,
+		"disks":     disks,
+	})
+}
+
+// describeDisks lists the disks of the virtual machine followed by those
+// hotplugged into the running instance only, without persisting them
+func describeDisks(vm *virtv1.VirtualMachine, vmi *virtv1.VirtualMachineInstance) []diskInfo {
+	statuses := map[string]*virtv1.VolumeStatus{}
+	if vmi != nil {
+		for i := range vmi.Status.VolumeStatus {
+			statuses[vmi.Status.VolumeStatus[i].Name] = &vmi.Status.VolumeStatus[i]
+		}
+	}
+
+	disks := []diskInfo{}
+	seen := map[string]bool{}
+	spec := vm.Spec.Template.Spec
+	for _, disk := range spec.Domain.Devices.Disks {
+		disks = append(disks, describeDisk(disk, spec.Volumes, statuses[disk.Name], true))
+		seen[disk.Name] = true
+	}
+	if vmi != nil {
+		for _, disk := range vmi.Spec.Domain.Devices.Disks {
+			if !seen[disk.Name] {
+				disks = append(disks, describeDisk(disk, vmi.Spec.Volumes, statuses[disk.Name], false))
+			}
+		}
+	}
+	return disks
+}
+
+func describeDisk(disk virtv1.Disk, volumes []virtv1.Volume, status *virtv1.VolumeStatus, persisted bool) diskInfo {
+	info := diskInfo{
+		Name:      disk.Name,
+		BootOrder: disk.BootOrder,
+		Persisted: persisted,
+		Status:    status,
+	}
+	switch {
+	case disk.CDRom != nil:
+		info.Device = "cdrom"
+		info.Bus = disk.CDRom.Bus
+		info.ReadOnly = disk.CDRom.ReadOnly == nil || *disk.CDRom.ReadOnly
+	case disk.LUN != nil:
+		info.Device = "lun"
+		info.Bus = disk.LUN.Bus
+		info.ReadOnly = disk.LUN.ReadOnly
+	default:
+		info.Device = "disk"
+		if disk.Disk != nil {
+			info.Bus = disk.Disk.Bus
+			info.ReadOnly = disk.Disk.ReadOnly
+		}
+	}
+	if status != nil && status.HotplugVolume != nil {
+		info.Hotplugged = true
+	}
 
-	var diskNames []string
-	for _, disk := range vm.Spec.Template.Spec.Domain.Devices.Disks {
-		diskNames = append(diskNames, disk.Name)
+	for _, volume := range volumes {
+		if volume.Name != disk.Name {
+			continue
+		}
+		info.Source = describeVolumeSource(volume)
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.Hotpluggable {
+			info.Hotplugged = true
+		}
+		if volume.DataVolume != nil && volume.DataVolume.Hotpluggable {
+			info.Hotplugged = true
+		}
+		return info
 	}
+	info.Warnings = append(info.Warnings, fmt.Sprintf("no volume named %s backs this disk", disk.Name))
+	return info
+}
 
-	disks := "No disks found"
-	if len(diskNames) > 0 {
-		disks = strings.Join(diskNames, ", ")
+func describeVolumeSource(volume virtv1.Volume) *volumeSource {
+	source := volume.VolumeSource
+	switch {
+	case source.ContainerDisk != nil:
+		return &volumeSource{Type: "containerDisk", Image: source.ContainerDisk.Image}
+	case source.PersistentVolumeClaim != nil:
+		return &volumeSource{Type: "persistentVolumeClaim", ClaimName: source.PersistentVolumeClaim.ClaimName}
+	case source.DataVolume != nil:
+		return &volumeSource{Type: "dataVolume", DataVolume: source.DataVolume.Name, ClaimName: source.DataVolume.Name}
+	case source.CloudInitNoCloud != nil:
+		return &volumeSource{Type: "cloudInitNoCloud", Secret: secretRefName(source.CloudInitNoCloud.UserDataSecretRef)}
+	case source.CloudInitConfigDrive != nil:
+		return &volumeSource{Type: "cloudInitConfigDrive", Secret: secretRefName(source.CloudInitConfigDrive.UserDataSecretRef)}
+	case source.Ephemeral != nil:
+		claimName := ""
+		if source.Ephemeral.PersistentVolumeClaim != nil {
+			claimName = source.Ephemeral.PersistentVolumeClaim.ClaimName
+		}
+		return &volumeSource{Type: "ephemeral", ClaimName: claimName}
+	case source.EmptyDisk != nil:
+		return &volumeSource{Type: "emptyDisk"}
+	case source.HostDisk != nil:
+		return &volumeSource{Type: "hostDisk"}
+	case source.ConfigMap != nil:
+		return &volumeSource{Type: "configMap", ConfigMap: source.ConfigMap.Name}
+	case source.Secret != nil:
+		return &volumeSource{Type: "secret", Secret: source.Secret.SecretName}
+	case source.ServiceAccount != nil:
+		return &volumeSource{Type: "serviceAccount"}
+	case source.DownwardAPI != nil:
+		return &volumeSource{Type: "downwardAPI"}
+	case source.Sysprep != nil:
+		return &volumeSource{Type: "sysprep"}
+	case source.MemoryDump != nil:
+		return &volumeSource{Type: "memoryDump", ClaimName: source.MemoryDump.ClaimName}
 	}
+	return &volumeSource{Type: "unknown"}
+}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: disks,
-			},
-		},
-	}, nil
+func secretRefName(ref *corev1.LocalObjectReference) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Name
+}
+
+// addStorageDetails looks up the PersistentVolumeClaim and DataVolume behind
+// the disk, recording lookup failures as warnings rather than failing the
+// whole inventory
+func addStorageDetails(ctx context.Context, virtClient kubecli.KubevirtClient, namespace string, disk *diskInfo) {
+	if disk.Source == nil {
+		return
+	}
+	if disk.Source.DataVolume != "" {
+		dataVolume, err := virtClient.CdiClient().CdiV1beta1().DataVolumes(namespace).Get(ctx, disk.Source.DataVolume, metav1.GetOptions{})
+		switch {
+		case k8serrors.IsNotFound(err):
+			disk.Warnings = append(disk.Warnings, fmt.Sprintf("data volume %s not found", disk.Source.DataVolume))
+		case err != nil:
+			disk.Warnings = append(disk.Warnings, fmt.Sprintf("unable to read data volume %s: %v", disk.Source.DataVolume, err))
+		default:
+			disk.DataVolume = &dataVolumeInfo{
+				Name:     dataVolume.Name,
+				Phase:    string(dataVolume.Status.Phase),
+				Progress: string(dataVolume.Status.Progress),
+			}
+		}
+	}
+	if disk.Source.ClaimName == "" {
+		return
+	}
+	claim, err := virtClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, disk.Source.ClaimName, metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		// Data volumes create their claim once the import or clone starts
+		if disk.DataVolume == nil {
+			disk.Warnings = append(disk.Warnings, fmt.Sprintf("persistent volume claim %s not found", disk.Source.ClaimName))
+		}
+		return
+	case err != nil:
+		disk.Warnings = append(disk.Warnings, fmt.Sprintf("unable to read persistent volume claim %s: %v", disk.Source.ClaimName, err))
+		return
+	}
+	info := &claimInfo{
+		Name:        claim.Name,
+		Phase:       claim.Status.Phase,
+		AccessModes: claim.Spec.AccessModes,
+		VolumeMode:  claim.Spec.VolumeMode,
+	}
+	if claim.Spec.StorageClassName != nil {
+		info.StorageClass = *claim.Spec.StorageClassName
+	}
+	if capacity, ok := claim.Status.Capacity[corev1.ResourceStorage]; ok {
+		info.Capacity = &capacity
+	}
+	if requested, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		info.Requested = &requested
+	}
+	disk.Claim = info
 }
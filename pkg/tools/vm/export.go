@@ -0,0 +1,281 @@
+package vm
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	exportv1beta1 "kubevirt.io/api/export/v1beta1"
+	snapshotv1beta1 "kubevirt.io/api/snapshot/v1beta1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+const (
+	// exportPollInterval and exportTimeout bound how long export_vm waits
+	// for the export server to become ready
+	exportPollInterval = 2 * time.Second
+	exportTimeout      = 5 * time.Minute
+
+	// exportTokenHeader carries the export token when downloading from the export server
+	exportTokenHeader = "x-kubevirt-export-token"
+	// exportTokenKey is the key of the token within the export token secret
+	exportTokenKey = "token"
+	// manifestTimeout bounds the download of the exported manifest
+	manifestTimeout = 30 * time.Second
+)
+
+func Export(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	source, err := exportSource(request.GetString("source_kind", "vm"), name)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	ttl := exportv1beta1.DefaultDurationTTL
+	if raw := request.GetString("ttl", ""); raw != "" {
+		if ttl, err = time.ParseDuration(raw); err != nil || ttl <= 0 {
+			return newToolResultErr(fmt.Errorf("ttl must be a positive duration such as 30m or 2h, got %q", raw))
+		}
+	}
+	exportName := request.GetString("export_name", "")
+	includeCert := request.GetBool("include_cert", false)
+
+	export := &exportv1beta1.VirtualMachineExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      exportName,
+			Namespace: namespace,
+		},
+		Spec: exportv1beta1.VirtualMachineExportSpec{
+			Source:      source,
+			TTLDuration: &metav1.Duration{Duration: ttl},
+		},
+	}
+	if exportName == "" {
+		export.GenerateName = name + "-export-"
+	}
+	export, err = virtClient.VirtualMachineExport(namespace).Create(ctx, export, metav1.CreateOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	export, err = waitForExportReady(ctx, virtClient, export)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	result := map[string]interface{}{
+		"export": export.Name,
+		"source": fmt.Sprintf("%s/%s", source.Kind, source.Name),
+		"phase":  export.Status.Phase,
+	}
+	if export.Status.TTLExpirationTime != nil {
+		result["expires"] = export.Status.TTLExpirationTime
+	}
+	if export.Status.TokenSecretRef != nil {
+		result["tokenSecret"] = *export.Status.TokenSecretRef
+		result["tokenHeader"] = exportTokenHeader
+	}
+	if links := export.Status.Links; links != nil {
+		if links.Internal != nil {
+			result["internal"] = exportLinkSummary(links.Internal, includeCert)
+		}
+		if links.External != nil {
+			result["external"] = exportLinkSummary(links.External, includeCert)
+		}
+		if manifest, err := fetchExportManifest(ctx, virtClient, export); err != nil {
+			result["manifestError"] = err.Error()
+		} else if manifest != "" {
+			result["manifest"] = manifest
+		}
+	}
+	return newToolResultJSON(result)
+}
+
+func exportSource(kind, name string) (corev1.TypedLocalObjectReference, error) {
+	switch kind {
+	case "vm":
+		return corev1.TypedLocalObjectReference{
+			APIGroup: virtualMachineAPIGroup(),
+			Kind:     "VirtualMachine",
+			Name:     name,
+		}, nil
+	case "snapshot":
+		group := snapshotv1beta1.SchemeGroupVersion.Group
+		return corev1.TypedLocalObjectReference{
+			APIGroup: &group,
+			Kind:     "VirtualMachineSnapshot",
+			Name:     name,
+		}, nil
+	case "pvc":
+		return corev1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: name,
+		}, nil
+	}
+	return corev1.TypedLocalObjectReference{}, fmt.Errorf("unsupported source_kind %q, expected vm, snapshot or pvc", kind)
+}
+
+// exportLinkSummary lists the download URL of every exported volume keyed by
+// format, along with the certificate of the export server when requested
+func exportLinkSummary(link *exportv1beta1.VirtualMachineExportLink, includeCert bool) map[string]interface{} {
+	volumes := make([]map[string]interface{}, 0, len(link.Volumes))
+	for _, volume := range link.Volumes {
+		formats := map[string]string{}
+		for _, format := range volume.Formats {
+			formats[string(format.Format)] = format.Url
+		}
+		volumes = append(volumes, map[string]interface{}{
+			"name":    volume.Name,
+			"formats": formats,
+		})
+	}
+	summary := map[string]interface{}{
+		"volumes": volumes,
+	}
+	switch {
+	case link.Cert == "":
+		summary["cert"] = "not provided, verify the server against the system roots"
+	case includeCert:
+		summary["cert"] = link.Cert
+	default:
+		summary["cert"] = "provided by the export, set include_cert to return it PEM encoded"
+	}
+	if len(link.Manifests) > 0 {
+		manifests := map[string]string{}
+		for _, manifest := range link.Manifests {
+			manifests[string(manifest.Type)] = manifest.Url
+		}
+		summary["manifests"] = manifests
+	}
+	return summary
+}
+
+// fetchExportManifest downloads the manifest of every exported object,
+// preferring the external link since the server usually runs outside of the
+// cluster. An empty manifest is returned for sources without one, such as PVCs.
+func fetchExportManifest(ctx context.Context, virtClient kubecli.KubevirtClient, export *exportv1beta1.VirtualMachineExport) (string, error) {
+	link := export.Status.Links.External
+	if link == nil || manifestURL(link) == "" {
+		link = export.Status.Links.Internal
+	}
+	if link == nil || manifestURL(link) == "" {
+		return "", nil
+	}
+	if export.Status.TokenSecretRef == nil {
+		return "", fmt.Errorf("export %s has no token secret", export.Name)
+	}
+
+	secret, err := virtClient.CoreV1().Secrets(export.Namespace).Get(ctx, *export.Status.TokenSecretRef, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to read export token: %w", err)
+	}
+
+	roots, err := exportRootCAs(link.Cert)
+	if err != nil {
+		return "", fmt.Errorf("export %s has an invalid certificate", export.Name)
+	}
+	httpClient := &http.Client{
+		Timeout: manifestTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, manifestURL(link), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(exportTokenHeader, string(secret.Data[exportTokenKey]))
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to download manifest: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to download manifest: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to download manifest: %w", err)
+	}
+	return string(body), nil
+}
+
+// exportRootCAs returns the roots trusted when downloading from the export
+// server. Links without a certificate are served by ingresses or routes
+// trusted through the system roots, signalled by a nil pool.
+func exportRootCAs(cert string) (*x509.CertPool, error) {
+	if cert == "" {
+		return nil, nil
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(cert)) {
+		return nil, fmt.Errorf("no certificate found in PEM data")
+	}
+	return roots, nil
+}
+
+func manifestURL(link *exportv1beta1.VirtualMachineExportLink) string {
+	for _, manifest := range link.Manifests {
+		if manifest.Type == exportv1beta1.AllManifests {
+			return manifest.Url
+		}
+	}
+	return ""
+}
+
+func waitForExportReady(ctx context.Context, virtClient kubecli.KubevirtClient, export *exportv1beta1.VirtualMachineExport) (*exportv1beta1.VirtualMachineExport, error) {
+	err := wait.PollUntilContextTimeout(ctx, exportPollInterval, exportTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := virtClient.VirtualMachineExport(export.Namespace).Get(ctx, export.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		export = current
+		if current.Status == nil {
+			return false, nil
+		}
+		switch current.Status.Phase {
+		case exportv1beta1.Ready:
+			return true, nil
+		case exportv1beta1.Skipped, exportv1beta1.Terminated:
+			return false, fmt.Errorf("export %s is %s%s", current.Name, current.Status.Phase, exportConditionMessage(current))
+		}
+		return false, nil
+	})
+	if wait.Interrupted(err) {
+		return nil, fmt.Errorf("timed out after %s waiting for export %s to become ready%s", exportTimeout, export.Name, exportConditionMessage(export))
+	}
+	return export, err
+}
+
+func exportConditionMessage(export *exportv1beta1.VirtualMachineExport) string {
+	if export.Status == nil {
+		return ""
+	}
+	for _, cond := range export.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue && cond.Message != "" {
+			return ": " + cond.Message
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package vm
+
+// Unexported helpers exposed to the external vm_test package
+
+// LifecycleAction exposes the lifecycleAction type to the vm_test package
+type LifecycleAction = lifecycleAction
+
+var (
+	WithMultusInterface     = withMultusInterface
+	DefaultPodInterface     = defaultPodInterface
+	DescribeDisks           = describeDisks
+	DescribeDisk            = describeDisk
+	DescribeVolumeSource    = describeVolumeSource
+	LifecycleTransition     = lifecycleTransition
+	SnapshotConsistency     = snapshotConsistency
+	ExportLinkSummary       = exportLinkSummary
+	ExportRootCAs           = exportRootCAs
+	MigrationState          = migrationState
+	ForceStopNoop           = forceStopNoop
+	RestoreFailed           = restoreFailed
+	RestoreConditionMessage = restoreConditionMessage
+	ActionStart             = actionStart
+	ActionStop              = actionStop
+	ActionPause             = actionPause
+	ActionUnpause           = actionUnpause
+)
+
+// CaptureText writes the chunks to a captureWriter keeping limit bytes of
+// text, returning what it captured
+func CaptureText(limit int, chunks ...string) (string, bool) {
+	w := &captureWriter{limit: limit, rawLimit: limit * 4, full: make(chan struct{})}
+	for _, chunk := range chunks {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			break
+		}
+	}
+	return w.captured()
+}
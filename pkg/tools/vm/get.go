@@ -2,10 +2,10 @@ package vm
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/output"
 	"github.com/mark3labs/mcp-go/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -58,6 +58,10 @@ func GetStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolR
 	if err != nil {
 		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
 	}
+	format, err := output.ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return newToolResultErr(err)
+	}
 
 	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
@@ -94,19 +98,7 @@ func GetStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolR
 		statusInfo["stateChangeRequests"] = requests
 	}
 
-	resultJSON, err := json.MarshalIndent(statusInfo, "", "  ")
-	if err != nil {
-		return newToolResultErr(err)
-	}
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	return newToolResultFormatted(statusInfo, format)
 }
 
 func GetConditions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -123,6 +115,10 @@ func GetConditions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallT
 	if err != nil {
 		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
 	}
+	format, err := output.ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return newToolResultErr(err)
+	}
 
 	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
@@ -155,19 +151,7 @@ func GetConditions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallT
 		conditionsInfo["conditions"] = conditions
 	}
 
-	resultJSON, err := json.MarshalIndent(conditionsInfo, "", "  ")
-	if err != nil {
-		return newToolResultErr(err)
-	}
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	return newToolResultFormatted(conditionsInfo, format)
 }
 
 func GetPhase(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -184,6 +168,10 @@ func GetPhase(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolRe
 	if err != nil {
 		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
 	}
+	format, err := output.ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return newToolResultErr(err)
+	}
 
 	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
@@ -202,17 +190,5 @@ func GetPhase(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolRe
 		phaseInfo["runStrategy"] = string(*vm.Spec.RunStrategy)
 	}
 
-	resultJSON, err := json.MarshalIndent(phaseInfo, "", "  ")
-	if err != nil {
-		return newToolResultErr(err)
-	}
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	return newToolResultFormatted(phaseInfo, format)
 }
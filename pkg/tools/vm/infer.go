@@ -0,0 +1,134 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/tools/containerdisks"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+	instancetypeapi "kubevirt.io/api/instancetype"
+	"kubevirt.io/client-go/kubecli"
+)
+
+// matcherConfig holds the instance type and preference arguments of
+// create_vm, either named explicitly or inferred from the boot volume
+type matcherConfig struct {
+	instancetype      string
+	instancetypeKind  string
+	preference        string
+	preferenceKind    string
+	inferInstancetype bool
+	inferPreference   bool
+
+	// inferFromVolume is the volume KubeVirt infers from when the boot
+	// volume carries the default labels itself, empty when they are read
+	// client side from the image
+	inferFromVolume string
+}
+
+func parseMatchers(request mcp.CallToolRequest) (*matcherConfig, error) {
+	config := &matcherConfig{
+		instancetype:      request.GetString("instancetype", ""),
+		preference:        request.GetString("preference", ""),
+		inferInstancetype: request.GetBool("infer_instancetype", false),
+		inferPreference:   request.GetBool("infer_preference", false),
+	}
+	if config.inferInstancetype && config.instancetype != "" {
+		return nil, fmt.Errorf("infer_instancetype and instancetype parameters are mutually exclusive")
+	}
+	if config.inferPreference && config.preference != "" {
+		return nil, fmt.Errorf("infer_preference and preference parameters are mutually exclusive")
+	}
+	return config, nil
+}
+
+func (c *matcherConfig) inferring() bool {
+	return c.inferInstancetype || c.inferPreference
+}
+
+// fromLabels takes the instance type and preference that are neither given
+// nor inferred by KubeVirt from the default labels of the boot source,
+// failing when a requested inference finds no label
+func (c *matcherConfig) fromLabels(labels map[string]string, source string) error {
+	if c.instancetype == "" && !(c.inferInstancetype && c.inferFromVolume != "") {
+		c.instancetype = labels[instancetypeapi.DefaultInstancetypeLabel]
+		c.instancetypeKind = labels[instancetypeapi.DefaultInstancetypeKindLabel]
+		if c.instancetype == "" && c.inferInstancetype {
+			return fmt.Errorf("cannot infer instance type: %s has no %s label", source, instancetypeapi.DefaultInstancetypeLabel)
+		}
+	}
+	if c.preference == "" && !(c.inferPreference && c.inferFromVolume != "") {
+		c.preference = labels[instancetypeapi.DefaultPreferenceLabel]
+		c.preferenceKind = labels[instancetypeapi.DefaultPreferenceKindLabel]
+		if c.preference == "" && c.inferPreference {
+			return fmt.Errorf("cannot infer preference: %s has no %s label", source, instancetypeapi.DefaultPreferenceLabel)
+		}
+	}
+	return nil
+}
+
+// apply sets the matchers of the virtual machine. Names without a kind refer
+// to the namespaced object when one exists in the namespace of the virtual
+// machine and to the cluster wide one otherwise.
+func (c *matcherConfig) apply(ctx context.Context, virtClient kubecli.KubevirtClient, vm *virtv1.VirtualMachine) {
+	switch {
+	case c.inferInstancetype && c.inferFromVolume != "":
+		vm.Spec.Instancetype = &virtv1.InstancetypeMatcher{InferFromVolume: c.inferFromVolume}
+	case c.instancetype != "":
+		kind := c.instancetypeKind
+		if kind == "" {
+			kind = clusterInstancetypeKind
+			if _, err := virtClient.VirtualMachineInstancetype(vm.Namespace).Get(ctx, c.instancetype, metav1.GetOptions{}); err == nil {
+				kind = instancetypeKind
+			}
+		}
+		vm.Spec.Instancetype = &virtv1.InstancetypeMatcher{Name: c.instancetype, Kind: kind}
+	}
+
+	switch {
+	case c.inferPreference && c.inferFromVolume != "":
+		vm.Spec.Preference = &virtv1.PreferenceMatcher{InferFromVolume: c.inferFromVolume}
+	case c.preference != "":
+		kind := c.preferenceKind
+		if kind == "" {
+			kind = clusterPreferenceKind
+			if _, err := virtClient.VirtualMachinePreference(vm.Namespace).Get(ctx, c.preference, metav1.GetOptions{}); err == nil {
+				kind = preferenceKind
+			}
+		}
+		vm.Spec.Preference = &virtv1.PreferenceMatcher{Name: c.preference, Kind: kind}
+	}
+}
+
+// describeMatchers summarises the matchers of the virtual machine, which
+// KubeVirt resolves to a name and kind on creation when inferring
+func describeMatchers(vm *virtv1.VirtualMachine) string {
+	var message string
+	if matcher := vm.Spec.Instancetype; matcher != nil {
+		if matcher.InferFromVolume != "" {
+			message += fmt.Sprintf(", instancetype inferred from volume %s", matcher.InferFromVolume)
+		} else {
+			message += fmt.Sprintf(", instancetype %s (%s)", matcher.Name, matcher.Kind)
+		}
+	}
+	if matcher := vm.Spec.Preference; matcher != nil {
+		if matcher.InferFromVolume != "" {
+			message += fmt.Sprintf(", preference inferred from volume %s", matcher.InferFromVolume)
+		} else {
+			message += fmt.Sprintf(", preference %s (%s)", matcher.Name, matcher.Kind)
+		}
+	}
+	return message
+}
+
+// matchersFromImage reads the default labels from the config of a container
+// disk or registry image built for the architecture
+func matchersFromImage(ctx context.Context, matchers *matcherConfig, image, architecture string) error {
+	labels, err := containerdisks.ImageLabels(ctx, image, architecture)
+	if err != nil {
+		return fmt.Errorf("cannot infer instance type or preference: %w", err)
+	}
+	return matchers.fromLabels(labels, "image "+image)
+}
@@ -0,0 +1,307 @@
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	virtv1 "kubevirt.io/api/core/v1"
+	instancetypev1beta1 "kubevirt.io/api/instancetype/v1beta1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+const (
+	clusterInstancetypeKind = "VirtualMachineClusterInstancetype"
+	instancetypeKind        = "VirtualMachineInstancetype"
+	clusterPreferenceKind   = "VirtualMachineClusterPreference"
+	preferenceKind          = "VirtualMachinePreference"
+
+	// revisionPollInterval and revisionTimeout bound how long the set tools
+	// wait for the controller to capture the new instance type or preference
+	revisionPollInterval = time.Second
+	revisionTimeout      = 15 * time.Second
+)
+
+func SetInstancetype(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	instancetypeName, err := request.RequireString("instancetype")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("instancetype parameter required: %w", err))
+	}
+	kind := request.GetString("kind", clusterInstancetypeKind)
+	if kind != clusterInstancetypeKind && kind != instancetypeKind {
+		return newToolResultErr(fmt.Errorf("unsupported kind %q, expected %s or %s", kind, clusterInstancetypeKind, instancetypeKind))
+	}
+	restart := request.GetBool("restart", false)
+
+	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	spec, err := resolveInstancetype(ctx, virtClient, namespace, instancetypeName, kind)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if vm.Spec.Preference != nil {
+		preference, err := resolvePreference(ctx, virtClient, namespace, vm.Spec.Preference.Name, vm.Spec.Preference.Kind)
+		if err != nil {
+			return newToolResultErr(err)
+		}
+		if err := checkRequirements(vm.Spec.Preference.Name, preference.Requirements, spec.CPU.Guest, spec.Memory.Guest); err != nil {
+			return newToolResultErr(fmt.Errorf("instance type %s %w", instancetypeName, err))
+		}
+	}
+
+	previous := ""
+	if vm.Spec.Instancetype != nil {
+		previous = vm.Spec.Instancetype.Name
+	}
+	// Replacing the whole matcher drops revisionName so that the controller
+	// captures a ControllerRevision of the new instance type
+	matcher := virtv1.InstancetypeMatcher{Name: instancetypeName, Kind: kind}
+	patch, err := matcherPatch("/spec/instancetype", vm.Spec.Instancetype != nil, matcher)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	return applyMatcherChange(ctx, virtClient, vm, patch, restart, map[string]interface{}{
+		"instancetype": instancetypeName,
+		"kind":         kind,
+		"previous":     previous,
+	}, func(vm *virtv1.VirtualMachine) bool {
+		return vm.Status.InstancetypeRef != nil && vm.Status.InstancetypeRef.Name == instancetypeName && vm.Status.InstancetypeRef.Kind == kind
+	})
+}
+
+func SetPreference(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	preferenceName, err := request.RequireString("preference")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("preference parameter required: %w", err))
+	}
+	kind := request.GetString("kind", clusterPreferenceKind)
+	if kind != clusterPreferenceKind && kind != preferenceKind {
+		return newToolResultErr(fmt.Errorf("unsupported kind %q, expected %s or %s", kind, clusterPreferenceKind, preferenceKind))
+	}
+	restart := request.GetBool("restart", false)
+
+	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	preference, err := resolvePreference(ctx, virtClient, namespace, preferenceName, kind)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	cpu, memory, err := guestResources(ctx, virtClient, vm)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if err := checkRequirements(preferenceName, preference.Requirements, cpu, memory); err != nil {
+		return newToolResultErr(fmt.Errorf("virtual machine %s %w", name, err))
+	}
+
+	previous := ""
+	if vm.Spec.Preference != nil {
+		previous = vm.Spec.Preference.Name
+	}
+	// Replacing the whole matcher drops revisionName so that the controller
+	// captures a ControllerRevision of the new preference
+	matcher := virtv1.PreferenceMatcher{Name: preferenceName, Kind: kind}
+	patch, err := matcherPatch("/spec/preference", vm.Spec.Preference != nil, matcher)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	return applyMatcherChange(ctx, virtClient, vm, patch, restart, map[string]interface{}{
+		"preference": preferenceName,
+		"kind":       kind,
+		"previous":   previous,
+	}, func(vm *virtv1.VirtualMachine) bool {
+		return vm.Status.PreferenceRef != nil && vm.Status.PreferenceRef.Name == preferenceName && vm.Status.PreferenceRef.Kind == kind
+	})
+}
+
+func resolveInstancetype(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, name, kind string) (*instancetypev1beta1.VirtualMachineInstancetypeSpec, error) {
+	if kind == instancetypeKind {
+		instancetype, err := virtClient.VirtualMachineInstancetype(namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("instance type %s not found in namespace %s", name, namespace)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &instancetype.Spec, nil
+	}
+	instancetype, err := virtClient.VirtualMachineClusterInstancetype().Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("cluster instance type %s not found", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &instancetype.Spec, nil
+}
+
+func resolvePreference(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, name, kind string) (*instancetypev1beta1.VirtualMachinePreferenceSpec, error) {
+	if kind == preferenceKind {
+		preference, err := virtClient.VirtualMachinePreference(namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("preference %s not found in namespace %s", name, namespace)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &preference.Spec, nil
+	}
+	preference, err := virtClient.VirtualMachineClusterPreference().Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("cluster preference %s not found", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preference.Spec, nil
+}
+
+// guestResources returns the guest vCPUs and memory of a virtual machine,
+// taken from its instance type when it references one
+func guestResources(ctx context.Context, virtClient kubecli.KubevirtClient, vm *virtv1.VirtualMachine) (uint32, resource.Quantity, error) {
+	if vm.Spec.Instancetype != nil {
+		spec, err := resolveInstancetype(ctx, virtClient, vm.Namespace, vm.Spec.Instancetype.Name, vm.Spec.Instancetype.Kind)
+		if err != nil {
+			return 0, resource.Quantity{}, err
+		}
+		return spec.CPU.Guest, spec.Memory.Guest, nil
+	}
+
+	var cpu uint32 = 1
+	var memory resource.Quantity
+	if vm.Spec.Template == nil {
+		return cpu, memory, nil
+	}
+	domain := vm.Spec.Template.Spec.Domain
+	if domain.CPU != nil {
+		cpu = max(domain.CPU.Sockets, 1) * max(domain.CPU.Cores, 1) * max(domain.CPU.Threads, 1)
+	}
+	if domain.Memory != nil && domain.Memory.Guest != nil {
+		memory = *domain.Memory.Guest
+	} else if request, ok := domain.Resources.Requests[corev1.ResourceMemory]; ok {
+		memory = request
+	}
+	return cpu, memory, nil
+}
+
+// checkRequirements validates guest resources against the requirements of a
+// preference, the error completes a sentence started by the caller
+func checkRequirements(preferenceName string, requirements *instancetypev1beta1.PreferenceRequirements, cpu uint32, memory resource.Quantity) error {
+	if requirements == nil {
+		return nil
+	}
+	if requirements.CPU != nil && cpu < requirements.CPU.Guest {
+		return fmt.Errorf("provides %d vCPUs but preference %s requires at least %d", cpu, preferenceName, requirements.CPU.Guest)
+	}
+	if requirements.Memory != nil && memory.Cmp(requirements.Memory.Guest) < 0 {
+		return fmt.Errorf("provides %s of memory but preference %s requires at least %s", memory.String(), preferenceName, requirements.Memory.Guest.String())
+	}
+	return nil
+}
+
+func matcherPatch(path string, exists bool, matcher interface{}) ([]byte, error) {
+	op := "add"
+	if exists {
+		op = "replace"
+	}
+	return json.Marshal([]map[string]interface{}{
+		{"op": op, "path": path, "value": matcher},
+	})
+}
+
+// applyMatcherChange patches the instance type or preference of a virtual
+// machine, waits for the controller to observe it and reports whether the
+// change was applied live or requires a restart, restarting when asked to
+func applyMatcherChange(ctx context.Context, virtClient kubecli.KubevirtClient, vm *virtv1.VirtualMachine, patch []byte, restart bool, result map[string]interface{}, observed func(*virtv1.VirtualMachine) bool) (*mcp.CallToolResult, error) {
+	vm, err := virtClient.VirtualMachine(vm.Namespace).Patch(ctx, vm.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	result["vm"] = vm.Name
+
+	pollErr := wait.PollUntilContextTimeout(ctx, revisionPollInterval, revisionTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := virtClient.VirtualMachine(vm.Namespace).Get(ctx, vm.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		vm = current
+		return observed(current), nil
+	})
+	if pollErr != nil && !wait.Interrupted(pollErr) {
+		return newToolResultErr(pollErr)
+	}
+
+	_, err = virtClient.VirtualMachineInstance(vm.Namespace).Get(ctx, vm.Name, metav1.GetOptions{})
+	running := err == nil
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return newToolResultErr(err)
+	}
+
+	switch {
+	case !running:
+		result["applied"] = "on next start"
+	case !observed(vm):
+		result["applied"] = "pending, the controller has not observed the change yet"
+	case hasVMCondition(vm, virtv1.VirtualMachineRestartRequired):
+		result["applied"] = "RestartRequired"
+		if restart {
+			if err := virtClient.VirtualMachine(vm.Namespace).Restart(ctx, vm.Name, &virtv1.RestartOptions{}); err != nil {
+				return newToolResultErr(fmt.Errorf("change applied but restart failed: %w", err))
+			}
+			result["applied"] = "restarted"
+		}
+	default:
+		result["applied"] = "live"
+	}
+	return newToolResultJSON(result)
+}
+
+func hasVMCondition(vm *virtv1.VirtualMachine, conditionType virtv1.VirtualMachineConditionType) bool {
+	for _, cond := range vm.Status.Conditions {
+		if cond.Type == conditionType && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,333 @@
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+var networkAttachmentDefinitionsResource = schema.GroupVersionResource{
+	Group:    "k8s.cni.cncf.io",
+	Version:  "v1",
+	Resource: "network-attachment-definitions",
+}
+
+const (
+	// interfacePollInterval and interfaceTimeout bound how long the interface
+	// tools wait for the change to be reflected in the VMI interface status
+	interfacePollInterval = 2 * time.Second
+	interfaceTimeout      = time.Minute
+)
+
+func AddInterface(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	interfaceName, err := request.RequireString("interface_name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("interface_name parameter required: %w", err))
+	}
+	networkName, err := request.RequireString("network_attachment_definition")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("network_attachment_definition parameter required: %w", err))
+	}
+	macAddress := request.GetString("mac_address", "")
+
+	if err := requireNetworkAttachmentDefinition(ctx, virtClient, namespace, networkName); err != nil {
+		return newToolResultErr(err)
+	}
+
+	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if vm.Spec.Template == nil {
+		return newToolResultErr(fmt.Errorf("virtual machine %s has no template", name))
+	}
+	spec := &vm.Spec.Template.Spec
+	if findInterface(spec.Domain.Devices.Interfaces, interfaceName) != nil || findNetwork(spec.Networks, interfaceName) != nil ||
+		(interfaceName == podNetworkName && len(spec.Domain.Devices.Interfaces) == 0) {
+		return newToolResultErr(fmt.Errorf("virtual machine %s already has an interface or network named %s", name, interfaceName))
+	}
+
+	var podInterface *virtv1.Interface
+	if hasImplicitPodInterface(spec) {
+		config, err := kubevirtConfiguration(ctx, virtClient)
+		if err != nil {
+			return newToolResultErr(err)
+		}
+		if podInterface, err = defaultPodInterface(config); err != nil {
+			return newToolResultErr(err)
+		}
+	}
+	interfaces, networks := withMultusInterface(spec, podInterface, interfaceName, networkName, macAddress)
+	if err := patchInterfaces(ctx, virtClient, vm, interfaces, networks); err != nil {
+		return newToolResultErr(err)
+	}
+
+	result := map[string]interface{}{
+		"vm":        name,
+		"interface": interfaceName,
+		"network":   networkName,
+	}
+	status, applied, err := waitForInterfaceStatus(ctx, virtClient, namespace, name, interfaceName, func(status *virtv1.VirtualMachineInstanceNetworkInterface) bool {
+		return status != nil
+	})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	result["applied"] = applied
+	if status != nil {
+		result["interfaceStatus"] = interfaceStatusSummary(status)
+	}
+	return newToolResultJSON(result)
+}
+
+func RemoveInterface(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	interfaceName, err := request.RequireString("interface_name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("interface_name parameter required: %w", err))
+	}
+
+	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if vm.Spec.Template == nil {
+		return newToolResultErr(fmt.Errorf("virtual machine %s has no template", name))
+	}
+	spec := &vm.Spec.Template.Spec
+	iface := findInterface(spec.Domain.Devices.Interfaces, interfaceName)
+	if iface == nil {
+		return newToolResultErr(fmt.Errorf("virtual machine %s has no interface named %s", name, interfaceName))
+	}
+	if iface.Bridge == nil {
+		return newToolResultErr(fmt.Errorf("interface %s does not use the bridge binding, only bridge interfaces can be hot-unplugged", interfaceName))
+	}
+	if network := findNetwork(spec.Networks, interfaceName); network == nil || network.Multus == nil || network.Multus.Default {
+		return newToolResultErr(fmt.Errorf("interface %s is not backed by a secondary Multus network, only those can be hot-unplugged", interfaceName))
+	}
+
+	// Marking the interface absent lets KubeVirt unplug it from the guest and
+	// drop it together with its network from the spec once it is gone
+	iface.State = virtv1.InterfaceStateAbsent
+	if err := patchInterfaces(ctx, virtClient, vm, spec.Domain.Devices.Interfaces, spec.Networks); err != nil {
+		return newToolResultErr(err)
+	}
+
+	result := map[string]interface{}{
+		"vm":        name,
+		"interface": interfaceName,
+	}
+	_, applied, err := waitForInterfaceStatus(ctx, virtClient, namespace, name, interfaceName, func(status *virtv1.VirtualMachineInstanceNetworkInterface) bool {
+		return status == nil
+	})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	result["applied"] = applied
+	return newToolResultJSON(result)
+}
+
+// hasImplicitPodInterface reports whether the spec relies on the pod network
+// KubeVirt attaches to virtual machines that declare no interfaces
+func hasImplicitPodInterface(spec *virtv1.VirtualMachineInstanceSpec) bool {
+	autoattach := spec.Domain.Devices.AutoattachPodInterface
+	return len(spec.Domain.Devices.Interfaces) == 0 && len(spec.Networks) == 0 && (autoattach == nil || *autoattach)
+}
+
+// defaultPodInterface returns the pod interface KubeVirt attaches implicitly,
+// bound as spec.configuration.network.defaultNetworkInterface selects. It
+// fails when the configuration cannot be read or names an unsupported
+// binding, as guessing would change the network of the virtual machine.
+func defaultPodInterface(config *virtv1.KubeVirtConfiguration) (*virtv1.Interface, error) {
+	if config == nil {
+		return nil, fmt.Errorf("unable to read the default network interface binding of the cluster, declare the pod interface of the virtual machine explicitly first")
+	}
+	binding := ""
+	if config.NetworkConfiguration != nil {
+		binding = config.NetworkConfiguration.NetworkInterface
+	}
+	switch binding {
+	case "", "masquerade":
+		return virtv1.DefaultMasqueradeNetworkInterface(), nil
+	case "bridge":
+		return virtv1.DefaultBridgeNetworkInterface(), nil
+	}
+	return nil, fmt.Errorf("default network interface binding %q is not supported, declare the pod interface of the virtual machine explicitly first", binding)
+}
+
+// withMultusInterface returns the interfaces and networks of the spec with a
+// bridge bound interface on the Multus network appended, hotplug only
+// supports those. A spec relying on the implicit pod network gets the given
+// pod interface made explicit first so that it is not lost.
+func withMultusInterface(spec *virtv1.VirtualMachineInstanceSpec, podInterface *virtv1.Interface, interfaceName, networkName, macAddress string) ([]virtv1.Interface, []virtv1.Network) {
+	interfaces := slices.Clone(spec.Domain.Devices.Interfaces)
+	networks := slices.Clone(spec.Networks)
+	if hasImplicitPodInterface(spec) && podInterface != nil {
+		interfaces = append(interfaces, *podInterface)
+		networks = append(networks, *virtv1.DefaultPodNetwork())
+	}
+
+	// Every interface is paired with the network of the same name
+	interfaces = append(interfaces, virtv1.Interface{
+		Name:                   interfaceName,
+		InterfaceBindingMethod: virtv1.InterfaceBindingMethod{Bridge: &virtv1.InterfaceBridge{}},
+		MacAddress:             macAddress,
+	})
+	networks = append(networks, virtv1.Network{
+		Name: interfaceName,
+		NetworkSource: virtv1.NetworkSource{
+			Multus: &virtv1.MultusNetwork{NetworkName: networkName},
+		},
+	})
+	return interfaces, networks
+}
+
+// requireNetworkAttachmentDefinition fails when the Multus network, given as
+// name or namespace/name, does not exist
+func requireNetworkAttachmentDefinition(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, networkName string) error {
+	nadNamespace, nadName := namespace, networkName
+	if ns, n, found := strings.Cut(networkName, "/"); found {
+		nadNamespace, nadName = ns, n
+	}
+	_, err := virtClient.DynamicClient().Resource(networkAttachmentDefinitionsResource).Namespace(nadNamespace).Get(ctx, nadName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return fmt.Errorf("network attachment definition %s not found in namespace %s", nadName, nadNamespace)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read network attachment definition %s: %w", networkName, err)
+	}
+	return nil
+}
+
+// patchInterfaces replaces the interfaces and networks of the virtual machine
+// template, failing if the virtual machine changed since it was read
+func patchInterfaces(ctx context.Context, virtClient kubecli.KubevirtClient, vm *virtv1.VirtualMachine, interfaces []virtv1.Interface, networks []virtv1.Network) error {
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": vm.ResourceVersion},
+		{"op": "add", "path": "/spec/template/spec/domain/devices/interfaces", "value": interfaces},
+		{"op": "add", "path": "/spec/template/spec/networks", "value": networks},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = virtClient.VirtualMachine(vm.Namespace).Patch(ctx, vm.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// waitForInterfaceStatus polls the VMI interface status until done reports
+// true, the virtual machine reports RestartRequired or the timeout expires,
+// returning the last status seen and how the change was applied
+func waitForInterfaceStatus(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, name, interfaceName string, done func(*virtv1.VirtualMachineInstanceNetworkInterface) bool) (*virtv1.VirtualMachineInstanceNetworkInterface, string, error) {
+	var status *virtv1.VirtualMachineInstanceNetworkInterface
+	applied := "pending, the virtual machine instance has not reported the change yet"
+	err := wait.PollUntilContextTimeout(ctx, interfacePollInterval, interfaceTimeout, true, func(ctx context.Context) (bool, error) {
+		vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			status, applied = nil, "on next start"
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		status = findInterfaceStatus(vmi, interfaceName)
+		if done(status) {
+			applied = "live"
+			return true, nil
+		}
+		vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if hasVMCondition(vm, virtv1.VirtualMachineRestartRequired) {
+			applied = string(virtv1.VirtualMachineRestartRequired)
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil && !wait.Interrupted(err) {
+		return nil, "", err
+	}
+	return status, applied, nil
+}
+
+func findInterface(interfaces []virtv1.Interface, name string) *virtv1.Interface {
+	for i := range interfaces {
+		if interfaces[i].Name == name {
+			return &interfaces[i]
+		}
+	}
+	return nil
+}
+
+func findNetwork(networks []virtv1.Network, name string) *virtv1.Network {
+	for i := range networks {
+		if networks[i].Name == name {
+			return &networks[i]
+		}
+	}
+	return nil
+}
+
+func findInterfaceStatus(vmi *virtv1.VirtualMachineInstance, name string) *virtv1.VirtualMachineInstanceNetworkInterface {
+	for i := range vmi.Status.Interfaces {
+		if vmi.Status.Interfaces[i].Name == name {
+			return &vmi.Status.Interfaces[i]
+		}
+	}
+	return nil
+}
+
+func interfaceStatusSummary(status *virtv1.VirtualMachineInstanceNetworkInterface) map[string]interface{} {
+	summary := map[string]interface{}{
+		"mac":        status.MAC,
+		"infoSource": status.InfoSource,
+	}
+	if status.InterfaceName != "" {
+		summary["guestInterface"] = status.InterfaceName
+	}
+	if len(status.IPs) > 0 {
+		summary["ipAddresses"] = status.IPs
+	}
+	if status.LinkState != "" {
+		summary["linkState"] = status.LinkState
+	}
+	return summary
+}
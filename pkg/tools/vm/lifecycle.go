@@ -0,0 +1,89 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+// lifecycleAction is a power state change requested through the lifecycle tools
+type lifecycleAction string
+
+const (
+	actionStart   lifecycleAction = "start"
+	actionStop    lifecycleAction = "stop"
+	actionPause   lifecycleAction = "pause"
+	actionUnpause lifecycleAction = "unpause"
+)
+
+// lifecyclePreflight reads the virtual machine and checks whether the action
+// can be applied to its current state. A non empty message means the virtual
+// machine is already in the requested state and the action is a no-op.
+func lifecyclePreflight(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, name string, action lifecycleAction) (string, error) {
+	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	runStrategy, err := vm.RunStrategy()
+	if err != nil {
+		return "", fmt.Errorf("cannot %s: %w", action, err)
+	}
+	return lifecycleTransition(vm.Name, vm.Status.PrintableStatus, runStrategy, action)
+}
+
+// lifecycleTransition implements the state machine behind lifecyclePreflight
+func lifecycleTransition(name string, status virtv1.VirtualMachinePrintableStatus, runStrategy virtv1.VirtualMachineRunStrategy, action lifecycleAction) (string, error) {
+	switch action {
+	case actionStart:
+		switch status {
+		case virtv1.VirtualMachineStatusRunning, virtv1.VirtualMachineStatusMigrating:
+			return fmt.Sprintf("VM %s is already running", name), nil
+		case virtv1.VirtualMachineStatusStarting, virtv1.VirtualMachineStatusProvisioning, virtv1.VirtualMachineStatusWaitingForVolumeBinding:
+			return fmt.Sprintf("VM %s is already starting (%s)", name, status), nil
+		case virtv1.VirtualMachineStatusPaused:
+			return "", fmt.Errorf("cannot start: VM %s is Paused, use unpause_vm to resume it", name)
+		case virtv1.VirtualMachineStatusStopping, virtv1.VirtualMachineStatusTerminating:
+			return "", fmt.Errorf("cannot start: VM %s is %s, wait for it to stop", name, status)
+		}
+		if status != virtv1.VirtualMachineStatusStopped && status != "" && runStrategy == virtv1.RunStrategyAlways {
+			return fmt.Sprintf("VM %s is already set to run with runStrategy %s but is %s, check its conditions", name, runStrategy, status), nil
+		}
+	case actionStop:
+		switch status {
+		case virtv1.VirtualMachineStatusStopped:
+			return fmt.Sprintf("VM %s is already stopped", name), nil
+		case virtv1.VirtualMachineStatusStopping, virtv1.VirtualMachineStatusTerminating:
+			return fmt.Sprintf("VM %s is already stopping (%s)", name, status), nil
+		}
+		if status == "" && runStrategy == virtv1.RunStrategyHalted {
+			return fmt.Sprintf("VM %s is already stopped", name), nil
+		}
+	case actionPause:
+		switch status {
+		case virtv1.VirtualMachineStatusPaused:
+			return fmt.Sprintf("VM %s is already paused", name), nil
+		case virtv1.VirtualMachineStatusRunning:
+		default:
+			return "", fmt.Errorf("cannot pause: VM %s is %s", name, printableStatus(status))
+		}
+	case actionUnpause:
+		switch status {
+		case virtv1.VirtualMachineStatusPaused:
+		case virtv1.VirtualMachineStatusRunning:
+			return fmt.Sprintf("VM %s is not paused, it is already running", name), nil
+		default:
+			return "", fmt.Errorf("cannot unpause: VM %s is %s", name, printableStatus(status))
+		}
+	}
+	return "", nil
+}
+
+func printableStatus(status virtv1.VirtualMachinePrintableStatus) virtv1.VirtualMachinePrintableStatus {
+	if status == "" {
+		return virtv1.VirtualMachineStatusUnknown
+	}
+	return status
+}
@@ -2,11 +2,13 @@ package vm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/inventory"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/pagination"
 	"github.com/mark3labs/mcp-go/mcp"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func List(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -15,25 +17,58 @@ func List(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult
 		return newToolResultErr(err)
 	}
 
+	page, err := pagination.FromToolRequest(request)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	// Listing across several namespaces returns an inventory grouped by namespace
+	allNamespaces := request.GetBool("all_namespaces", false)
+	namespaces := request.GetStringSlice("namespaces", nil)
+	if allNamespaces || len(namespaces) > 0 {
+		if allNamespaces && len(namespaces) > 0 {
+			return newToolResultErr(fmt.Errorf("all_namespaces and namespaces parameters are mutually exclusive"))
+		}
+		result, err := inventory.ListVMs(ctx, virtClient, namespaces, page)
+		if err != nil {
+			return newToolResultErr(err)
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return newToolResultErr(err)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: string(resultJSON),
+				},
+			},
+		}, nil
+	}
+
 	namespace, err := request.RequireString("namespace")
 	if err != nil {
 		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
 	}
-	vms, err := virtClient.VirtualMachine(namespace).List(context.Background(), metav1.ListOptions{})
+
+	vms, err := virtClient.VirtualMachine(namespace).List(ctx, page.ListOptions())
 	if err != nil {
 		return newToolResultErr(err)
 	}
 
-	names := ""
+	names := make([]string, 0, len(vms.Items))
 	for _, vm := range vms.Items {
-		names += fmt.Sprintf("%s\n", vm.Name)
+		names = append(names, vm.Name)
 	}
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: names,
+				Text: pagination.NameList(page, vms.ListMeta, names),
 			},
 		},
 	}, nil
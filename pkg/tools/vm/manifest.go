@@ -0,0 +1,146 @@
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+	sigsjson "sigs.k8s.io/json"
+	"sigs.k8s.io/yaml"
+)
+
+func CreateFromManifest(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	manifest, err := request.RequireString("manifest")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("manifest parameter required: %w", err))
+	}
+	dryRunOnly := request.GetBool("dry_run", false)
+
+	vm, problems := decodeVirtualMachine(manifest, namespace)
+	if len(problems) > 0 {
+		return newToolResultErr(validationError("manifest", problems))
+	}
+
+	// The server side dry-run runs the KubeVirt admission webhooks, catching
+	// everything the schema alone cannot
+	if err := createVirtualMachine(ctx, virtClient, vm, true); err != nil {
+		return newToolResultErr(err)
+	}
+
+	result := map[string]interface{}{
+		"namespace": namespace,
+		"dryRun":    "passed",
+		"created":   false,
+	}
+	if vm.Name != "" {
+		result["vm"] = vm.Name
+	}
+	if dryRunOnly {
+		return newToolResultJSON(result)
+	}
+
+	if err := createVirtualMachine(ctx, virtClient, vm, false); err != nil {
+		return newToolResultErr(err)
+	}
+	result["vm"] = vm.Name
+	result["created"] = true
+	return newToolResultJSON(result)
+}
+
+// decodeVirtualMachine strictly decodes a YAML or JSON VirtualMachine
+// manifest, collecting every schema problem instead of stopping at the first
+func decodeVirtualMachine(manifest, namespace string) (*virtv1.VirtualMachine, []string) {
+	data, err := yaml.YAMLToJSON([]byte(manifest))
+	if err != nil {
+		return nil, []string{fmt.Sprintf("not a valid YAML or JSON document: %v", err)}
+	}
+
+	vm := &virtv1.VirtualMachine{}
+	strictErrs, err := sigsjson.UnmarshalStrict(data, vm)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	var problems []string
+	for _, strictErr := range strictErrs {
+		problems = append(problems, strictErr.Error())
+	}
+
+	if vm.APIVersion != virtv1.GroupVersion.String() {
+		problems = append(problems, fmt.Sprintf("apiVersion: must be %s, got %q", virtv1.GroupVersion.String(), vm.APIVersion))
+	}
+	if vm.Kind != virtv1.VirtualMachineGroupVersionKind.Kind {
+		problems = append(problems, fmt.Sprintf("kind: must be %s, got %q", virtv1.VirtualMachineGroupVersionKind.Kind, vm.Kind))
+	}
+	if vm.Name == "" && vm.GenerateName == "" {
+		problems = append(problems, "metadata.name: name or generateName is required")
+	}
+	// Manifests may only target the namespace the tool was called for
+	switch vm.Namespace {
+	case "":
+		vm.Namespace = namespace
+	case namespace:
+	default:
+		problems = append(problems, fmt.Sprintf("metadata.namespace: %q does not match the requested namespace %q", vm.Namespace, namespace))
+	}
+	if vm.Spec.Template == nil {
+		problems = append(problems, "spec.template: a virtual machine instance template is required")
+	}
+	if vm.Spec.Running != nil {
+		problems = append(problems, "spec.running: deprecated, use spec.runStrategy instead")
+	}
+	vm.ResourceVersion = ""
+	vm.UID = ""
+	vm.Status = virtv1.VirtualMachineStatus{}
+	return vm, problems
+}
+
+// createVirtualMachine creates the virtual machine, or only validates it
+// server side when dryRun is set, updating vm with the created object
+func createVirtualMachine(ctx context.Context, virtClient kubecli.KubevirtClient, vm *virtv1.VirtualMachine, dryRun bool) error {
+	options := metav1.CreateOptions{}
+	stage := "creation"
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+		stage = "server side dry-run"
+	}
+	created, err := virtClient.VirtualMachine(vm.Namespace).Create(ctx, vm.DeepCopy(), options)
+	if err != nil {
+		var statusErr *k8serrors.StatusError
+		if errors.As(err, &statusErr) && statusErr.ErrStatus.Details != nil && len(statusErr.ErrStatus.Details.Causes) > 0 {
+			problems := make([]string, 0, len(statusErr.ErrStatus.Details.Causes))
+			for _, cause := range statusErr.ErrStatus.Details.Causes {
+				if cause.Field != "" {
+					problems = append(problems, fmt.Sprintf("%s: %s", cause.Field, cause.Message))
+				} else {
+					problems = append(problems, cause.Message)
+				}
+			}
+			return validationError(stage, problems)
+		}
+		return fmt.Errorf("%s failed: %w", stage, err)
+	}
+	if !dryRun {
+		*vm = *created
+	}
+	return nil
+}
+
+func validationError(stage string, problems []string) error {
+	return fmt.Errorf("%s failed validation:\n- %s", stage, strings.Join(problems, "\n- "))
+}
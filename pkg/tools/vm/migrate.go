@@ -0,0 +1,263 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/output"
+	"github.com/mark3labs/mcp-go/mcp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+var migrationsResource = schema.GroupVersionResource{
+	Group:    virtv1.GroupVersion.Group,
+	Version:  virtv1.GroupVersion.Version,
+	Resource: "virtualmachineinstancemigrations",
+}
+
+func Migrate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	nodeSelector, err := parseStringMap(request.GetArguments()["added_node_selector"], "added_node_selector")
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	vmi, err := getRunningVMI(ctx, virtClient, namespace, name)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	inFlight, err := findMigration(ctx, virtClient, namespace, name, true)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if inFlight != nil {
+		return newToolResultErr(fmt.Errorf("migration %s of %s is already in progress", inFlight.Name, name))
+	}
+
+	migration := &virtv1.VirtualMachineInstanceMigration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: virtv1.GroupVersion.String(),
+			Kind:       "VirtualMachineInstanceMigration",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: name + "-migration-",
+			Namespace:    namespace,
+		},
+		Spec: virtv1.VirtualMachineInstanceMigrationSpec{
+			VMIName: name,
+		},
+	}
+	if len(nodeSelector) > 0 {
+		migration, err = createTargetedMigration(ctx, virtClient, migration, nodeSelector)
+	} else {
+		migration, err = virtClient.VirtualMachineInstanceMigration(namespace).Create(ctx, migration, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	result := map[string]interface{}{
+		"migration":  migration.Name,
+		"vm":         name,
+		"namespace":  namespace,
+		"phase":      migration.Status.Phase,
+		"sourceNode": vmi.Status.NodeName,
+	}
+	if len(nodeSelector) > 0 {
+		result["addedNodeSelector"] = nodeSelector
+	}
+	return newToolResultJSON(result)
+}
+
+func GetMigrationStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+
+	// The instance may be gone after a completed migration, for example when
+	// the guest shut down, the migration still records the outcome
+	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		vmi = nil
+	} else if err != nil {
+		return newToolResultErr(err)
+	}
+	migration, err := findMigration(ctx, virtClient, namespace, name, false)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if vmi == nil && migration == nil {
+		return newToolResultErr(fmt.Errorf("virtual machine %s is not running and has no recorded migration", name))
+	}
+
+	result := map[string]interface{}{
+		"vm":        name,
+		"namespace": namespace,
+		"running":   vmi != nil,
+	}
+	state := migrationState(vmi, migration)
+	if vmi != nil {
+		result["nodeName"] = vmi.Status.NodeName
+	}
+	if migration != nil {
+		result["migration"] = migration.Name
+		result["phase"] = migration.Status.Phase
+	}
+	if state != nil {
+		for key, value := range output.MigrationStateSummary(state) {
+			result[key] = value
+		}
+	}
+	if state == nil {
+		result["dataTransferred"] = output.DataTransferredUnavailable
+	}
+	if migration == nil && state == nil {
+		result["status"] = "no migration has been recorded for this virtual machine"
+	}
+	return newToolResultJSON(result)
+}
+
+// migrationState returns the state of the latest migration. The instance
+// keeps the state of the last migration that ran, which only describes the
+// latest one when their UIDs match, a pending migration has no state yet.
+func migrationState(vmi *virtv1.VirtualMachineInstance, migration *virtv1.VirtualMachineInstanceMigration) *virtv1.VirtualMachineInstanceMigrationState {
+	var vmiState *virtv1.VirtualMachineInstanceMigrationState
+	if vmi != nil {
+		vmiState = vmi.Status.MigrationState
+	}
+	if migration == nil {
+		return vmiState
+	}
+	if vmiState != nil && vmiState.MigrationUID == migration.UID {
+		return vmiState
+	}
+	return migration.Status.MigrationState
+}
+
+func CancelMigration(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+
+	migration, err := findMigration(ctx, virtClient, namespace, name, true)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if migration == nil {
+		return newToolResultErr(fmt.Errorf("no migration of %s is in progress", name))
+	}
+
+	err = virtClient.VirtualMachineInstanceMigration(namespace).Delete(ctx, migration.Name, metav1.DeleteOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("cancelled migration %s of %s in phase %s", migration.Name, name, migration.Status.Phase),
+			},
+		},
+	}, nil
+}
+
+// getRunningVMI returns the instance of a virtual machine, failing with a
+// readable error when the virtual machine is not running
+func getRunningVMI(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, name string) (*virtv1.VirtualMachineInstance, error) {
+	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("virtual machine %s is not running", name)
+	}
+	return vmi, err
+}
+
+// findMigration returns the most recent migration of a virtual machine
+// instance, optionally only considering migrations that are still in progress
+func findMigration(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, name string, inFlight bool) (*virtv1.VirtualMachineInstanceMigration, error) {
+	selector := labels.SelectorFromSet(labels.Set{virtv1.MigrationSelectorLabel: name})
+	migrations, err := virtClient.VirtualMachineInstanceMigration(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *virtv1.VirtualMachineInstanceMigration
+	for i := range migrations.Items {
+		migration := &migrations.Items[i]
+		if migration.Spec.VMIName != name || (inFlight && migration.IsFinal()) {
+			continue
+		}
+		if latest == nil || latest.CreationTimestamp.Before(&migration.CreationTimestamp) {
+			latest = migration
+		}
+	}
+	return latest, nil
+}
+
+// createTargetedMigration creates a migration restricted to nodes matching
+// the selector. The vendored KubeVirt API predates spec.addedNodeSelector so
+// the migration is created through the dynamic client.
+func createTargetedMigration(ctx context.Context, virtClient kubecli.KubevirtClient, migration *virtv1.VirtualMachineInstanceMigration, nodeSelector map[string]string) (*virtv1.VirtualMachineInstanceMigration, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(migration)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	selector := make(map[string]interface{}, len(nodeSelector))
+	for key, value := range nodeSelector {
+		selector[key] = value
+	}
+	if err := unstructured.SetNestedMap(obj.Object, selector, "spec", "addedNodeSelector"); err != nil {
+		return nil, err
+	}
+
+	created, err := virtClient.DynamicClient().Resource(migrationsResource).Namespace(migration.Namespace).Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := &virtv1.VirtualMachineInstanceMigration{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(created.Object, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,283 @@
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+func Network(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+
+	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		vmi = nil
+	} else if err != nil {
+		return newToolResultErr(err)
+	}
+
+	if vm.Spec.Template == nil {
+		return newToolResultErr(fmt.Errorf("virtual machine %s has no template", name))
+	}
+
+	// KubeVirt attaches the pod network when no interface is given, the
+	// running instance shows the defaulted interface
+	spec := vm.Spec.Template.Spec
+	if len(spec.Domain.Devices.Interfaces) == 0 && vmi != nil {
+		spec = vmi.Spec
+	}
+
+	var warnings []string
+	reported := map[string]bool{}
+	interfaces := make([]map[string]interface{}, 0, len(spec.Domain.Devices.Interfaces))
+	for _, iface := range spec.Domain.Devices.Interfaces {
+		entry := map[string]interface{}{
+			"name":    iface.Name,
+			"binding": interfaceBinding(iface),
+		}
+		if iface.Model != "" {
+			entry["model"] = iface.Model
+		}
+		if iface.MacAddress != "" {
+			entry["macAddress"] = iface.MacAddress
+		}
+		if iface.State != "" {
+			entry["state"] = iface.State
+		}
+		if network := findNetwork(spec.Networks, iface.Name); network != nil {
+			entry["network"] = describeNetwork(ctx, virtClient, namespace, network)
+		} else {
+			warnings = append(warnings, fmt.Sprintf("interface %s has no network of the same name", iface.Name))
+		}
+		if vmi != nil {
+			if status := findInterfaceStatus(vmi, iface.Name); status != nil {
+				entry["status"] = interfaceStatusSummary(status)
+				reported[iface.Name] = true
+			} else if iface.State != virtv1.InterfaceStateAbsent {
+				entry["status"] = "not reported by the running instance"
+			}
+		}
+		interfaces = append(interfaces, entry)
+	}
+	if len(interfaces) == 0 && (spec.Domain.Devices.AutoattachPodInterface == nil || *spec.Domain.Devices.AutoattachPodInterface) {
+		warnings = append(warnings, "no interfaces are defined, KubeVirt attaches the pod network with masquerade binding on start")
+	}
+
+	result := map[string]interface{}{
+		"namespace":  namespace,
+		"vm":         name,
+		"running":    vmi != nil,
+		"interfaces": interfaces,
+	}
+	if vmi != nil {
+		// The guest agent also reports interfaces KubeVirt does not manage,
+		// such as bridges and container networks inside the guest
+		var guestOnly []map[string]interface{}
+		for i := range vmi.Status.Interfaces {
+			status := &vmi.Status.Interfaces[i]
+			if status.Name == "" || !reported[status.Name] {
+				guestOnly = append(guestOnly, interfaceStatusSummary(status))
+			}
+		}
+		if len(guestOnly) > 0 {
+			result["guestOnlyInterfaces"] = guestOnly
+		}
+	}
+
+	podLabels, labelSource, err := launcherPodLabels(ctx, virtClient, vm, vmi)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	services, err := selectingServices(ctx, virtClient, namespace, podLabels)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	result["services"] = services
+	result["serviceMatchLabels"] = labelSource
+	if len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+	return newToolResultJSON(result)
+}
+
+func interfaceBinding(iface virtv1.Interface) string {
+	switch {
+	case iface.Binding != nil:
+		return iface.Binding.Name
+	case iface.Masquerade != nil:
+		return "masquerade"
+	case iface.Bridge != nil:
+		return "bridge"
+	case iface.SRIOV != nil:
+		return "sriov"
+	case iface.DeprecatedPasst != nil:
+		return "passt"
+	case iface.DeprecatedSlirp != nil:
+		return "slirp"
+	case iface.DeprecatedMacvtap != nil:
+		return "macvtap"
+	}
+	return "unknown"
+}
+
+// describeNetwork reports the network of an interface, including the
+// NetworkAttachmentDefinition and CNI plugin behind Multus networks
+func describeNetwork(ctx context.Context, virtClient kubecli.KubevirtClient, namespace string, network *virtv1.Network) map[string]interface{} {
+	if network.Pod != nil {
+		entry := map[string]interface{}{"type": "pod"}
+		if network.Pod.VMNetworkCIDR != "" {
+			entry["vmNetworkCIDR"] = network.Pod.VMNetworkCIDR
+		}
+		return entry
+	}
+	if network.Multus == nil {
+		return map[string]interface{}{"type": "unknown"}
+	}
+
+	nadNamespace, nadName := namespace, network.Multus.NetworkName
+	if ns, n, found := strings.Cut(network.Multus.NetworkName, "/"); found {
+		nadNamespace, nadName = ns, n
+	}
+	attachment := map[string]interface{}{
+		"name":      nadName,
+		"namespace": nadNamespace,
+	}
+	nad, err := virtClient.DynamicClient().Resource(networkAttachmentDefinitionsResource).Namespace(nadNamespace).Get(ctx, nadName, metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		attachment["error"] = "not found"
+	case err != nil:
+		attachment["error"] = err.Error()
+	default:
+		config, _, _ := unstructured.NestedString(nad.Object, "spec", "config")
+		var cni struct {
+			Type    string `json:"type"`
+			Plugins []struct {
+				Type string `json:"type"`
+			} `json:"plugins"`
+		}
+		if json.Unmarshal([]byte(config), &cni) == nil {
+			if cni.Type == "" && len(cni.Plugins) > 0 {
+				cni.Type = cni.Plugins[0].Type
+			}
+			attachment["cniType"] = cni.Type
+		}
+		if resourceName := nad.GetAnnotations()["k8s.v1.cni.cncf.io/resourceName"]; resourceName != "" {
+			attachment["resourceName"] = resourceName
+		}
+	}
+	return map[string]interface{}{
+		"type":                        "multus",
+		"default":                     network.Multus.Default,
+		"networkAttachmentDefinition": attachment,
+	}
+}
+
+// launcherPodLabels returns the labels of the virt-launcher pod of a running
+// virtual machine, or the template labels the pod will carry once started
+func launcherPodLabels(ctx context.Context, virtClient kubecli.KubevirtClient, vm *virtv1.VirtualMachine, vmi *virtv1.VirtualMachineInstance) (map[string]string, string, error) {
+	templateLabels := map[string]string{}
+	for key, value := range vm.Spec.Template.ObjectMeta.Labels {
+		templateLabels[key] = value
+	}
+	// KubeVirt adds these to every virt-launcher pod
+	templateLabels[virtv1.AppLabel] = "virt-launcher"
+	templateLabels[virtv1.VirtualMachineNameLabel] = vm.Name
+	if vmi == nil {
+		return templateLabels, "virtual machine template", nil
+	}
+
+	pod, err := launcherPod(ctx, virtClient, vmi)
+	if err != nil {
+		return templateLabels, "virtual machine template", fmt.Errorf("%w, matching services against the template labels", err)
+	}
+	if pod == nil {
+		return templateLabels, "virtual machine template", nil
+	}
+	return pod.Labels, "virt-launcher pod " + pod.Name, nil
+}
+
+// launcherPod returns the active virt-launcher pod of a virtual machine
+// instance, nil when it has none
+func launcherPod(ctx context.Context, virtClient kubecli.KubevirtClient, vmi *virtv1.VirtualMachineInstance) (*corev1.Pod, error) {
+	selector := labels.SelectorFromSet(labels.Set{virtv1.CreatedByLabel: string(vmi.UID)})
+	pods, err := virtClient.CoreV1().Pods(vmi.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the virt-launcher pod: %w", err)
+	}
+	for i := range pods.Items {
+		if phase := pods.Items[i].Status.Phase; phase != corev1.PodSucceeded && phase != corev1.PodFailed {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// selectingServices lists the services whose selector matches the pod labels
+func selectingServices(ctx context.Context, virtClient kubecli.KubevirtClient, namespace string, podLabels map[string]string) ([]map[string]interface{}, error) {
+	services := []map[string]interface{}{}
+	list, err := virtClient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return services, fmt.Errorf("unable to list services: %w", err)
+	}
+	for _, service := range list.Items {
+		if len(service.Spec.Selector) == 0 || !labels.SelectorFromSet(service.Spec.Selector).Matches(labels.Set(podLabels)) {
+			continue
+		}
+		ports := make([]string, 0, len(service.Spec.Ports))
+		for _, port := range service.Spec.Ports {
+			entry := fmt.Sprintf("%d/%s->%s", port.Port, port.Protocol, port.TargetPort.String())
+			if port.NodePort != 0 {
+				entry += fmt.Sprintf(" (nodePort %d)", port.NodePort)
+			}
+			ports = append(ports, entry)
+		}
+		entry := map[string]interface{}{
+			"name":      service.Name,
+			"type":      service.Spec.Type,
+			"clusterIP": service.Spec.ClusterIP,
+			"ports":     ports,
+		}
+		var external []string
+		external = append(external, service.Spec.ExternalIPs...)
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				external = append(external, ingress.IP)
+			} else if ingress.Hostname != "" {
+				external = append(external, ingress.Hostname)
+			}
+		}
+		if len(external) > 0 {
+			entry["external"] = external
+		}
+		services = append(services, entry)
+	}
+	return services, nil
+}
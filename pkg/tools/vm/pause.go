@@ -6,8 +6,6 @@ import (
 
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
 	"github.com/mark3labs/mcp-go/mcp"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	virtv1 "kubevirt.io/api/core/v1"
 )
 
@@ -26,21 +24,17 @@ func Pause(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResul
 		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
 	}
 
-	// Use JSON patch to update RunStrategy to Manual and set paused state
-	patchData := []byte(`[{"op": "replace", "path": "/spec/runStrategy", "value": "Manual"}, {"op": "replace", "path": "/spec/running", "value": false}]`)
-	_, err = virtClient.VirtualMachine(namespace).Patch(ctx, name, types.JSONPatchType, patchData, metav1.PatchOptions{})
+	message, err := lifecyclePreflight(ctx, virtClient, namespace, name, actionPause)
 	if err != nil {
 		return newToolResultErr(err)
 	}
+	if message != "" {
+		return newToolResultText(message)
+	}
 
-	// Now pause the VMI if it exists
-	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
-	if err == nil && vmi != nil {
-		// Pause the VMI using subresource
-		err = virtClient.VirtualMachineInstance(namespace).Pause(ctx, vmi.Name, &virtv1.PauseOptions{})
-		if err != nil {
-			return newToolResultErr(fmt.Errorf("failed to pause VMI: %w", err))
-		}
+	// Pausing only freezes the running instance, the run strategy is left alone
+	if err := virtClient.VirtualMachineInstance(namespace).Pause(ctx, name, &virtv1.PauseOptions{}); err != nil {
+		return newToolResultErr(fmt.Errorf("failed to pause VMI: %w", err))
 	}
 
 	return &mcp.CallToolResult{
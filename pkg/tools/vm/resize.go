@@ -0,0 +1,260 @@
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+const (
+	// defaultMaxHotplugRatio mirrors the KubeVirt default used to derive
+	// maxSockets and maxGuest when neither the VM nor the cluster sets them
+	defaultMaxHotplugRatio = 4
+
+	// resizePollInterval and resizeTimeout bound how long resize_vm waits for
+	// the VMI to report the new topology
+	resizePollInterval = 2 * time.Second
+	resizeTimeout      = 2 * time.Minute
+)
+
+func Resize(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	sockets, err := parseSockets(request.GetArguments()["sockets"])
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	var memory *resource.Quantity
+	if raw := request.GetString("memory", ""); raw != "" {
+		quantity, err := resource.ParseQuantity(raw)
+		if err != nil || quantity.Sign() <= 0 {
+			return newToolResultErr(fmt.Errorf("memory must be a positive quantity such as 2Gi, got %q", raw))
+		}
+		memory = &quantity
+	}
+	if sockets == 0 && memory == nil {
+		return newToolResultErr(fmt.Errorf("sockets or memory parameter required"))
+	}
+
+	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if vm.Spec.Instancetype != nil {
+		return newToolResultErr(fmt.Errorf("virtual machine %s takes its CPU and memory from instance type %s, use set_vm_instancetype to resize it", name, vm.Spec.Instancetype.Name))
+	}
+	if vm.Spec.Template == nil {
+		return newToolResultErr(fmt.Errorf("virtual machine %s has no template", name))
+	}
+
+	config, err := kubevirtConfiguration(ctx, virtClient)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if config != nil && (config.VMRolloutStrategy == nil || *config.VMRolloutStrategy != virtv1.VMRolloutStrategyLiveUpdate) {
+		return newToolResultErr(fmt.Errorf("CPU and memory hotplug requires the %s vmRolloutStrategy in the KubeVirt configuration", virtv1.VMRolloutStrategyLiveUpdate))
+	}
+	if err := checkResizeLimits(vm, config, sockets, memory); err != nil {
+		return newToolResultErr(err)
+	}
+
+	before := specTopology(vm)
+	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	running := err == nil
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return newToolResultErr(err)
+	}
+	if running {
+		before = vmiTopology(vmi)
+	}
+
+	patch, err := resizePatch(sockets, memory)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	vm, err = virtClient.VirtualMachine(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	result := map[string]interface{}{
+		"vm":     name,
+		"before": before,
+	}
+	if !running {
+		result["after"] = specTopology(vm)
+		result["applied"] = "on next start"
+		return newToolResultJSON(result)
+	}
+
+	applied := "pending, the virtual machine instance has not reported the new topology yet"
+	err = wait.PollUntilContextTimeout(ctx, resizePollInterval, resizeTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if hasVMCondition(current, virtv1.VirtualMachineRestartRequired) {
+			applied = string(virtv1.VirtualMachineRestartRequired)
+			return true, nil
+		}
+		vmi, err = virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if hotplugComplete(vmi, sockets, memory) {
+			applied = "live"
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil && !wait.Interrupted(err) {
+		return newToolResultErr(err)
+	}
+	result["applied"] = applied
+	result["after"] = vmiTopology(vmi)
+	return newToolResultJSON(result)
+}
+
+func parseSockets(raw interface{}) (uint32, error) {
+	if raw == nil {
+		return 0, nil
+	}
+	sockets, ok := raw.(float64)
+	if !ok || sockets != float64(uint32(sockets)) || sockets < 1 {
+		return 0, fmt.Errorf("sockets parameter must be a positive integer")
+	}
+	return uint32(sockets), nil
+}
+
+// checkResizeLimits validates the requested sockets and memory against the
+// maximum the virtual machine can hotplug, falling back to the cluster wide
+// live update configuration the same way KubeVirt does
+func checkResizeLimits(vm *virtv1.VirtualMachine, config *virtv1.KubeVirtConfiguration, sockets uint32, memory *resource.Quantity) error {
+	var liveUpdate virtv1.LiveUpdateConfiguration
+	if config != nil && config.LiveUpdateConfiguration != nil {
+		liveUpdate = *config.LiveUpdateConfiguration
+	}
+	ratio := liveUpdate.MaxHotplugRatio
+	if ratio == 0 {
+		ratio = defaultMaxHotplugRatio
+	}
+	domain := vm.Spec.Template.Spec.Domain
+
+	if sockets != 0 {
+		current := uint32(1)
+		maxSockets := uint32(0)
+		if domain.CPU != nil {
+			current = max(domain.CPU.Sockets, 1)
+			maxSockets = domain.CPU.MaxSockets
+		}
+		if maxSockets == 0 && liveUpdate.MaxCpuSockets != nil {
+			maxSockets = *liveUpdate.MaxCpuSockets
+		}
+		if maxSockets == 0 {
+			maxSockets = current * ratio
+		}
+		if sockets > maxSockets {
+			return fmt.Errorf("sockets %d exceeds the maximum of %d sockets", sockets, maxSockets)
+		}
+	}
+
+	if memory != nil {
+		var maxGuest *resource.Quantity
+		if domain.Memory != nil {
+			maxGuest = domain.Memory.MaxGuest
+		}
+		if maxGuest == nil {
+			maxGuest = liveUpdate.MaxGuest
+		}
+		if maxGuest == nil && domain.Memory != nil && domain.Memory.Guest != nil {
+			limit := domain.Memory.Guest.DeepCopy()
+			limit.Mul(int64(ratio))
+			maxGuest = &limit
+		}
+		if maxGuest != nil && memory.Cmp(*maxGuest) > 0 {
+			return fmt.Errorf("memory %s exceeds the maximum guest memory of %s", memory.String(), maxGuest.String())
+		}
+	}
+	return nil
+}
+
+func resizePatch(sockets uint32, memory *resource.Quantity) ([]byte, error) {
+	domain := map[string]interface{}{}
+	if sockets != 0 {
+		domain["cpu"] = map[string]interface{}{"sockets": sockets}
+	}
+	if memory != nil {
+		domain["memory"] = map[string]interface{}{"guest": memory.String()}
+	}
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"domain": domain,
+				},
+			},
+		},
+	})
+}
+
+// hotplugComplete reports whether the VMI runs with the requested topology
+func hotplugComplete(vmi *virtv1.VirtualMachineInstance, sockets uint32, memory *resource.Quantity) bool {
+	if sockets != 0 && (vmi.Status.CurrentCPUTopology == nil || vmi.Status.CurrentCPUTopology.Sockets != sockets) {
+		return false
+	}
+	if memory != nil && (vmi.Status.Memory == nil || vmi.Status.Memory.GuestCurrent == nil || vmi.Status.Memory.GuestCurrent.Cmp(*memory) != 0) {
+		return false
+	}
+	return true
+}
+
+func specTopology(vm *virtv1.VirtualMachine) map[string]interface{} {
+	topology := map[string]interface{}{}
+	if vm.Spec.Template == nil {
+		return topology
+	}
+	domain := vm.Spec.Template.Spec.Domain
+	if domain.CPU != nil {
+		topology["sockets"] = domain.CPU.Sockets
+		topology["cores"] = domain.CPU.Cores
+		topology["threads"] = domain.CPU.Threads
+	}
+	if domain.Memory != nil && domain.Memory.Guest != nil {
+		topology["memory"] = domain.Memory.Guest.String()
+	}
+	return topology
+}
+
+func vmiTopology(vmi *virtv1.VirtualMachineInstance) map[string]interface{} {
+	topology := map[string]interface{}{}
+	if cpu := vmi.Status.CurrentCPUTopology; cpu != nil {
+		topology["sockets"] = cpu.Sockets
+		topology["cores"] = cpu.Cores
+		topology["threads"] = cpu.Threads
+	}
+	if vmi.Status.Memory != nil && vmi.Status.Memory.GuestCurrent != nil {
+		topology["memory"] = vmi.Status.Memory.GuestCurrent.String()
+	}
+	return topology
+}
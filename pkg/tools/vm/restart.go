@@ -3,11 +3,21 @@ package vm
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
 	"github.com/mark3labs/mcp-go/mcp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+const (
+	// powerPollInterval and powerTimeout bound how long the restart and stop
+	// tools wait to report the outcome of the request
+	powerPollInterval = 2 * time.Second
+	powerTimeout      = time.Minute
 )
 
 func Restart(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -24,47 +34,87 @@ func Restart(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolRes
 	if err != nil {
 		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
 	}
-
-	// Check if VM has a running VMI
-	_, err = virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	options, err := restartOptions(request.GetArguments()["grace_period"], request.GetBool("force", false))
 	if err != nil {
-		// If VMI doesn't exist, just start the VM
-		// Use JSON patch to update RunStrategy to avoid conflicts
-		patchData := []byte(`[{"op": "replace", "path": "/spec/runStrategy", "value": "Always"}]`)
-		_, err = virtClient.VirtualMachine(namespace).Patch(ctx, name, types.JSONPatchType, patchData, metav1.PatchOptions{})
+		return newToolResultErr(err)
+	}
+
+	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return newToolResultErr(err)
+		}
+		runStrategy, err := vm.RunStrategy()
 		if err != nil {
+			return newToolResultErr(fmt.Errorf("cannot restart: %w", err))
+		}
+		// The start subresource refuses VMs that are already set to run,
+		// KubeVirt recreates their instance on its own
+		if runStrategy == virtv1.RunStrategyAlways {
+			return newToolResultText(fmt.Sprintf("VM %s has no instance and is already restarting with runStrategy %s (%s)", name, runStrategy, printableStatus(vm.Status.PrintableStatus)))
+		}
+		if err := virtClient.VirtualMachine(namespace).Start(ctx, name, &virtv1.StartOptions{}); err != nil {
 			return newToolResultErr(err)
 		}
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("started %s (was not running)", name),
-				},
-			},
-		}, nil
+		return newToolResultText(fmt.Sprintf("started %s (was not running)", name))
 	}
-
-	// If VMI exists, restart by deleting the VMI (VM will recreate it)
-	err = virtClient.VirtualMachineInstance(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
 		return newToolResultErr(err)
 	}
 
-	// Ensure VM is set to restart by setting RunStrategy to Always
-	// Use JSON patch to update RunStrategy to avoid conflicts
-	patchData := []byte(`[{"op": "replace", "path": "/spec/runStrategy", "value": "Always"}]`)
-	_, err = virtClient.VirtualMachine(namespace).Patch(ctx, name, types.JSONPatchType, patchData, metav1.PatchOptions{})
-	if err != nil {
+	if err := virtClient.VirtualMachine(namespace).Restart(ctx, name, options); err != nil {
+		return newToolResultErr(err)
+	}
+
+	// The restart replaces the VMI, report on the new one once it runs
+	previousUID := vmi.UID
+	err = wait.PollUntilContextTimeout(ctx, powerPollInterval, powerTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		vmi = current
+		return current.UID != previousUID && current.Status.Phase == virtv1.Running, nil
+	})
+	if err != nil && !wait.Interrupted(err) {
 		return newToolResultErr(err)
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: fmt.Sprintf("restarted %s", name),
-			},
-		},
-	}, nil
+	forced := ""
+	if options.GracePeriodSeconds != nil {
+		forced = " forcefully"
+	}
+	switch {
+	case vmi.UID == previousUID:
+		return newToolResultText(fmt.Sprintf("restart of %s requested%s, the previous instance is still %s", name, forced, vmi.Status.Phase))
+	case vmi.Status.Phase != virtv1.Running:
+		return newToolResultText(fmt.Sprintf("restarted %s%s, the new instance is %s", name, forced, vmi.Status.Phase))
+	}
+	return newToolResultText(fmt.Sprintf("restarted %s%s, the new instance is running on node %s", name, forced, vmi.Status.NodeName))
+}
+
+// restartOptions mirrors virtctl, KubeVirt only accepts a grace period of zero
+// to force a restart
+func restartOptions(gracePeriod interface{}, force bool) (*virtv1.RestartOptions, error) {
+	options := &virtv1.RestartOptions{}
+	if gracePeriod != nil {
+		seconds, ok := gracePeriod.(float64)
+		if !ok || seconds != float64(int64(seconds)) || seconds < 0 {
+			return nil, fmt.Errorf("grace_period parameter must be a non-negative integer")
+		}
+		if !force {
+			return nil, fmt.Errorf("grace_period can only be set together with force")
+		}
+		if seconds != 0 {
+			return nil, fmt.Errorf("a forced restart only supports a grace_period of 0")
+		}
+	}
+	if force {
+		options.GracePeriodSeconds = new(int64)
+	}
+	return options, nil
 }
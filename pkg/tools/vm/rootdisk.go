@@ -0,0 +1,161 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+const rootDiskName = "rootdisk"
+
+// rootDiskConfig describes a persistent root disk imported or cloned by a
+// dataVolumeTemplate of the virtual machine
+type rootDiskConfig struct {
+	dataSourceNamespace string
+	dataSourceName      string
+	imageURL            string
+	size                *resource.Quantity
+	storageClass        string
+	accessMode          corev1.PersistentVolumeAccessMode
+	volumeMode          corev1.PersistentVolumeMode
+}
+
+// parseRootDisk reads the persistent root disk arguments of a tool call,
+// returning nil when the virtual machine should boot from a container disk
+func parseRootDisk(request mcp.CallToolRequest, namespace string) (*rootDiskConfig, error) {
+	dataSource := request.GetString("data_source", "")
+	imageURL := request.GetString("disk_image_url", "")
+	if dataSource == "" && imageURL == "" {
+		return nil, nil
+	}
+	if dataSource != "" && imageURL != "" {
+		return nil, fmt.Errorf("data_source and disk_image_url parameters are mutually exclusive")
+	}
+
+	config := &rootDiskConfig{
+		dataSourceNamespace: namespace,
+		dataSourceName:      dataSource,
+		imageURL:            imageURL,
+		storageClass:        request.GetString("storage_class", ""),
+	}
+	if ns, name, found := strings.Cut(dataSource, "/"); found {
+		config.dataSourceNamespace, config.dataSourceName = ns, name
+	}
+	if imageURL != "" && !strings.HasPrefix(imageURL, "docker://") && !strings.HasPrefix(imageURL, "http://") && !strings.HasPrefix(imageURL, "https://") {
+		return nil, fmt.Errorf("disk_image_url must be a docker:// registry or http(s):// URL, got %q", imageURL)
+	}
+
+	if raw := request.GetString("disk_size", ""); raw != "" {
+		size, err := resource.ParseQuantity(raw)
+		if err != nil || size.Sign() <= 0 {
+			return nil, fmt.Errorf("disk_size must be a positive quantity such as 30Gi, got %q", raw)
+		}
+		config.size = &size
+	} else if imageURL != "" {
+		// Clones can take the size of the source, imports cannot
+		return nil, fmt.Errorf("disk_size parameter required when importing from disk_image_url")
+	}
+
+	switch mode := corev1.PersistentVolumeAccessMode(request.GetString("access_mode", "")); mode {
+	case "", corev1.ReadWriteOnce, corev1.ReadWriteMany, corev1.ReadOnlyMany, corev1.ReadWriteOncePod:
+		config.accessMode = mode
+	default:
+		return nil, fmt.Errorf("unsupported access_mode %q, expected ReadWriteOnce, ReadWriteMany, ReadOnlyMany or ReadWriteOncePod", mode)
+	}
+	switch mode := corev1.PersistentVolumeMode(request.GetString("volume_mode", "")); mode {
+	case "", corev1.PersistentVolumeFilesystem, corev1.PersistentVolumeBlock:
+		config.volumeMode = mode
+	default:
+		return nil, fmt.Errorf("unsupported volume_mode %q, expected Filesystem or Block", mode)
+	}
+	return config, nil
+}
+
+// dataSource returns the DataSource the root disk is cloned from, nil when it
+// is imported from a URL
+func (c *rootDiskConfig) dataSource(ctx context.Context, virtClient kubecli.KubevirtClient) (*cdiv1beta1.DataSource, error) {
+	if c.dataSourceName == "" {
+		return nil, nil
+	}
+	dataSource, err := virtClient.CdiClient().CdiV1beta1().DataSources(c.dataSourceNamespace).Get(ctx, c.dataSourceName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("data source %s not found in namespace %s", c.dataSourceName, c.dataSourceNamespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dataSource, nil
+}
+
+// apply adds the dataVolumeTemplate, disk and volume of the root disk
+func (c *rootDiskConfig) apply(vm *virtv1.VirtualMachine) {
+	dataVolumeName := vm.Name + "-" + rootDiskName
+
+	storage := &cdiv1beta1.StorageSpec{}
+	if c.size != nil {
+		storage.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: *c.size}
+	}
+	if c.storageClass != "" {
+		storage.StorageClassName = &c.storageClass
+	}
+	if c.accessMode != "" {
+		storage.AccessModes = []corev1.PersistentVolumeAccessMode{c.accessMode}
+	}
+	if c.volumeMode != "" {
+		storage.VolumeMode = &c.volumeMode
+	}
+
+	spec := cdiv1beta1.DataVolumeSpec{Storage: storage}
+	switch {
+	case c.dataSourceName != "":
+		spec.SourceRef = &cdiv1beta1.DataVolumeSourceRef{
+			Kind:      cdiv1beta1.DataVolumeDataSource,
+			Namespace: &c.dataSourceNamespace,
+			Name:      c.dataSourceName,
+		}
+	case strings.HasPrefix(c.imageURL, "docker://"):
+		spec.Source = &cdiv1beta1.DataVolumeSource{
+			Registry: &cdiv1beta1.DataVolumeSourceRegistry{URL: &c.imageURL},
+		}
+	default:
+		spec.Source = &cdiv1beta1.DataVolumeSource{
+			HTTP: &cdiv1beta1.DataVolumeSourceHTTP{URL: c.imageURL},
+		}
+	}
+
+	vm.Spec.DataVolumeTemplates = append(vm.Spec.DataVolumeTemplates, virtv1.DataVolumeTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Name: dataVolumeName},
+		Spec:       spec,
+	})
+	template := &vm.Spec.Template.Spec
+	template.Domain.Devices.Disks = append(template.Domain.Devices.Disks, virtv1.Disk{
+		Name: rootDiskName,
+		DiskDevice: virtv1.DiskDevice{
+			Disk: &virtv1.DiskTarget{
+				Bus: "virtio",
+			},
+		},
+	})
+	template.Volumes = append(template.Volumes, virtv1.Volume{
+		Name: rootDiskName,
+		VolumeSource: virtv1.VolumeSource{
+			DataVolume: &virtv1.DataVolumeSource{Name: dataVolumeName},
+		},
+	})
+}
+
+func (c *rootDiskConfig) describe(vmName string) string {
+	if c.dataSourceName != "" {
+		return fmt.Sprintf("root disk %s-%s cloned from data source %s/%s", vmName, rootDiskName, c.dataSourceNamespace, c.dataSourceName)
+	}
+	return fmt.Sprintf("root disk %s-%s imported from %s", vmName, rootDiskName, c.imageURL)
+}
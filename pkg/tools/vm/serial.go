@@ -0,0 +1,268 @@
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+	kvcorev1 "kubevirt.io/client-go/kubevirt/typed/core/v1"
+)
+
+const (
+	// guestConsoleLogContainer is the virt-launcher sidecar streaming the
+	// serial console into the pod log when serial console logging is enabled
+	guestConsoleLogContainer = "guest-console-log"
+
+	serialDefaultSeconds  = 5
+	serialMaxSeconds      = 60
+	serialDefaultMaxBytes = 16 * 1024
+	serialMaxBytes        = 1024 * 1024
+	serialConnectTimeout  = 10 * time.Second
+	serialLogTailLines    = 1000
+)
+
+var (
+	errCaptureFull = errors.New("capture limit reached")
+
+	// ansiSequence matches CSI and OSC escape sequences and the remaining
+	// two byte escapes emitted by firmware, bootloaders and shells
+	ansiSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+)
+
+func SerialOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	source := request.GetString("source", "auto")
+	if source != "auto" && source != "console" && source != "log" {
+		return newToolResultErr(fmt.Errorf("unsupported source %q, expected auto, console or log", source))
+	}
+	args := request.GetArguments()
+	seconds, err := parseBoundedInt(args["duration_seconds"], "duration_seconds", serialDefaultSeconds, 1, serialMaxSeconds)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	maxBytes, err := parseBoundedInt(args["max_bytes"], "max_bytes", serialDefaultMaxBytes, 1, serialMaxBytes)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	sendNewline := request.GetBool("send_newline", false)
+
+	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return newToolResultErr(fmt.Errorf("VM %s is not running, it has no serial console", name))
+	}
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	result := map[string]interface{}{
+		"namespace": namespace,
+		"vm":        name,
+	}
+	var consoleErr error
+	if source != "log" {
+		output, truncated, err := captureSerialConsole(ctx, virtClient, vmi, time.Duration(seconds)*time.Second, maxBytes, sendNewline)
+		// A live capture only holds what the guest printed while connected,
+		// a guest hung at boot prints nothing and the log has the history
+		if err == nil && (output != "" || source == "console") {
+			result["source"] = "console"
+			result["durationSeconds"] = seconds
+			result["truncated"] = truncated
+			result["output"] = output
+			return newToolResultJSON(result)
+		}
+		if err == nil {
+			err = fmt.Errorf("no output within %d seconds", seconds)
+		}
+		if source == "console" {
+			return newToolResultErr(fmt.Errorf("unable to read the serial console of VM %s: %w", name, err))
+		}
+		consoleErr = err
+	}
+
+	output, truncated, err := readConsoleLog(ctx, virtClient, vmi, maxBytes)
+	if err != nil {
+		if consoleErr != nil {
+			return newToolResultErr(fmt.Errorf("serial console: %v; console log: %w", consoleErr, err))
+		}
+		return newToolResultErr(err)
+	}
+	result["source"] = guestConsoleLogContainer
+	result["truncated"] = truncated
+	result["output"] = output
+	if consoleErr != nil {
+		result["consoleError"] = consoleErr.Error()
+	}
+	return newToolResultJSON(result)
+}
+
+// captureSerialConsole streams the serial console for at most the duration or
+// until maxBytes of text remain after stripping escape sequences, optionally
+// sending a newline to make the guest print its prompt again
+func captureSerialConsole(ctx context.Context, virtClient kubecli.KubevirtClient, vmi *virtv1.VirtualMachineInstance, duration time.Duration, maxBytes int, sendNewline bool) (string, bool, error) {
+	if attach := vmi.Spec.Domain.Devices.AutoattachSerialConsole; attach != nil && !*attach {
+		return "", false, fmt.Errorf("the serial console is disabled by autoattachSerialConsole")
+	}
+	stream, err := virtClient.VirtualMachineInstance(vmi.Namespace).SerialConsole(vmi.Name, &kvcorev1.SerialConsoleOptions{ConnectionTimeout: serialConnectTimeout})
+	if err != nil {
+		return "", false, err
+	}
+
+	in, inWriter := io.Pipe()
+	out := &captureWriter{limit: maxBytes, rawLimit: maxBytes * 4, full: make(chan struct{})}
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- stream.Stream(kvcorev1.StreamOptions{In: in, Out: out})
+	}()
+	if sendNewline {
+		go inWriter.Write([]byte("\r"))
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-out.full:
+	case <-ctx.Done():
+	case err := <-streamErr:
+		if output, _ := out.captured(); output == "" && err != nil && !errors.Is(err, errCaptureFull) {
+			return "", false, err
+		}
+	}
+	// Closing the input ends the stream, which closes the websocket
+	inWriter.Close()
+
+	output, truncated := out.captured()
+	return output, truncated, nil
+}
+
+// captureWriter collects the stream until more than limit bytes of text
+// remain after stripping escape sequences, or rawLimit bytes were written,
+// failing further writes so that the stream stops
+type captureWriter struct {
+	mu       sync.Mutex
+	data     []byte
+	limit    int
+	rawLimit int
+	// checked is the length of data when the stripped text was last measured
+	checked   int
+	truncated bool
+	full      chan struct{}
+}
+
+func (w *captureWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.truncated {
+		return 0, errCaptureFull
+	}
+	written := min(len(p), w.rawLimit-len(w.data))
+	w.data = append(w.data, p[:written]...)
+	// Stripping never grows the text, so it is only measured once the raw
+	// data could exceed the limit and then again after every quarter limit
+	if len(w.data) >= w.rawLimit {
+		w.truncated = true
+	} else if len(w.data) > w.limit && len(w.data)-w.checked >= w.limit/4 {
+		w.checked = len(w.data)
+		w.truncated = len(stripANSI(string(w.data))) > w.limit
+	}
+	if w.truncated {
+		close(w.full)
+	}
+	if written < len(p) {
+		return written, errCaptureFull
+	}
+	return written, nil
+}
+
+// captured returns the stripped text, cut to limit bytes
+func (w *captureWriter) captured() (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	output := stripANSI(string(w.data))
+	if len(output) > w.limit {
+		return strings.ToValidUTF8(output[:w.limit], ""), true
+	}
+	return output, w.truncated
+}
+
+// readConsoleLog reads the last maxBytes of text, after stripping escape
+// sequences, of the serial console log kept by the guest-console-log
+// container of the virt-launcher pod
+func readConsoleLog(ctx context.Context, virtClient kubecli.KubevirtClient, vmi *virtv1.VirtualMachineInstance, maxBytes int) (string, bool, error) {
+	pod, err := launcherPod(ctx, virtClient, vmi)
+	if err != nil {
+		return "", false, err
+	}
+	if pod == nil {
+		return "", false, fmt.Errorf("VM %s has no running virt-launcher pod", vmi.Name)
+	}
+	hasLog := false
+	for _, container := range pod.Spec.Containers {
+		if container.Name == guestConsoleLogContainer {
+			hasLog = true
+		}
+	}
+	if !hasLog {
+		return "", false, fmt.Errorf("serial console logging is not enabled for VM %s, enable spec.template.spec.domain.devices.logSerialConsole or clear disableSerialConsoleLog in the KubeVirt configuration", vmi.Name)
+	}
+
+	tailLines := int64(serialLogTailLines)
+	logs, err := virtClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: guestConsoleLogContainer,
+		TailLines: &tailLines,
+	}).Stream(ctx)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to read the %s log of pod %s: %w", guestConsoleLogContainer, pod.Name, err)
+	}
+	defer logs.Close()
+	data, err := io.ReadAll(io.LimitReader(logs, serialMaxBytes*4))
+	if err != nil {
+		return "", false, fmt.Errorf("unable to read the %s log of pod %s: %w", guestConsoleLogContainer, pod.Name, err)
+	}
+
+	// The end of the log is what matters when a guest hangs
+	output := stripANSI(string(data))
+	if len(output) > maxBytes {
+		return strings.ToValidUTF8(output[len(output)-maxBytes:], ""), true, nil
+	}
+	return output, false, nil
+}
+
+// stripANSI removes terminal escape sequences, carriage returns and other
+// control characters, keeping newlines and tabs
+func stripANSI(text string) string {
+	text = ansiSequence.ReplaceAllString(strings.ToValidUTF8(text, ""), "")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, text)
+}
@@ -0,0 +1,430 @@
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/output"
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/pagination"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	virtv1 "kubevirt.io/api/core/v1"
+	snapshotv1beta1 "kubevirt.io/api/snapshot/v1beta1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+const (
+	// snapshotPollInterval and snapshotTimeout bound how long the snapshot
+	// tools wait for a snapshot to become ready or a restore to complete
+	snapshotPollInterval = 2 * time.Second
+	snapshotTimeout      = 5 * time.Minute
+)
+
+func CreateSnapshot(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	snapshotName := request.GetString("snapshot_name", "")
+	freeze := request.GetBool("freeze", false)
+	waitForReady := request.GetBool("wait", false)
+
+	// KubeVirt freezes the guest filesystems whenever a guest agent is
+	// connected, requiring a freeze means refusing snapshots without one
+	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		vmi = nil
+	} else if err != nil {
+		return newToolResultErr(err)
+	}
+	consistency := snapshotConsistency(vmi)
+	if freeze && consistency == consistencyCrash {
+		return newToolResultErr(fmt.Errorf("virtual machine %s is running without a connected guest agent so its filesystems cannot be frozen, set freeze to false to take a crash consistent snapshot", name))
+	}
+
+	snapshot := &snapshotv1beta1.VirtualMachineSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName,
+			Namespace: namespace,
+		},
+		Spec: snapshotv1beta1.VirtualMachineSnapshotSpec{
+			Source: corev1.TypedLocalObjectReference{
+				APIGroup: virtualMachineAPIGroup(),
+				Kind:     "VirtualMachine",
+				Name:     name,
+			},
+		},
+	}
+	if snapshotName == "" {
+		snapshot.GenerateName = name + "-snapshot-"
+	}
+	snapshot, err = virtClient.VirtualMachineSnapshot(namespace).Create(ctx, snapshot, metav1.CreateOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	if waitForReady {
+		snapshot, err = waitForSnapshot(ctx, virtClient, snapshot)
+		if err != nil {
+			return newToolResultErr(err)
+		}
+	}
+	summary := output.SnapshotSummary(snapshot, nil)
+	summary["consistency"] = consistency
+	return newToolResultJSON(summary)
+}
+
+func ListSnapshots(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name := request.GetString("name", "")
+	page, err := pagination.FromToolRequest(request)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	result, err := listSnapshotSummaries(ctx, virtClient, namespace, name, page)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	return newToolResultJSON(result)
+}
+
+func GetSnapshot(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	snapshotName, err := request.RequireString("snapshot_name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("snapshot_name parameter required: %w", err))
+	}
+
+	snapshot, err := virtClient.VirtualMachineSnapshot(namespace).Get(ctx, snapshotName, metav1.GetOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	var content *snapshotv1beta1.VirtualMachineSnapshotContent
+	if snapshot.Status != nil && snapshot.Status.VirtualMachineSnapshotContentName != nil {
+		content, err = virtClient.VirtualMachineSnapshotContent(namespace).Get(ctx, *snapshot.Status.VirtualMachineSnapshotContentName, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return newToolResultErr(err)
+		}
+	}
+
+	summary := output.SnapshotSummary(snapshot, content)
+	if snapshot.Status != nil && len(snapshot.Status.Conditions) > 0 {
+		conditions := make([]map[string]interface{}, 0, len(snapshot.Status.Conditions))
+		for _, cond := range snapshot.Status.Conditions {
+			conditions = append(conditions, map[string]interface{}{
+				"type":    cond.Type,
+				"status":  cond.Status,
+				"reason":  cond.Reason,
+				"message": cond.Message,
+			})
+		}
+		summary["conditions"] = conditions
+	}
+	return newToolResultJSON(summary)
+}
+
+func RestoreSnapshot(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	snapshotName, err := request.RequireString("snapshot_name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("snapshot_name parameter required: %w", err))
+	}
+	waitForComplete := request.GetBool("wait", true)
+
+	snapshot, err := virtClient.VirtualMachineSnapshot(namespace).Get(ctx, snapshotName, metav1.GetOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if snapshot.Status == nil || snapshot.Status.ReadyToUse == nil || !*snapshot.Status.ReadyToUse {
+		return newToolResultErr(fmt.Errorf("snapshot %s is not ready to use", snapshotName))
+	}
+	if _, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		return newToolResultErr(fmt.Errorf("virtual machine %s is running, stop it before restoring a snapshot", name))
+	} else if !k8serrors.IsNotFound(err) {
+		return newToolResultErr(err)
+	}
+
+	restore := &snapshotv1beta1.VirtualMachineRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: name + "-restore-",
+			Namespace:    namespace,
+		},
+		Spec: snapshotv1beta1.VirtualMachineRestoreSpec{
+			Target: corev1.TypedLocalObjectReference{
+				APIGroup: virtualMachineAPIGroup(),
+				Kind:     "VirtualMachine",
+				Name:     name,
+			},
+			VirtualMachineSnapshotName: snapshotName,
+		},
+	}
+	restore, err = virtClient.VirtualMachineRestore(namespace).Create(ctx, restore, metav1.CreateOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	if waitForComplete {
+		restore, err = waitForRestore(ctx, virtClient, restore)
+		if err != nil {
+			return newToolResultErr(err)
+		}
+	}
+
+	result := map[string]interface{}{
+		"restore":  restore.Name,
+		"vm":       name,
+		"snapshot": snapshotName,
+		"complete": restore.Status != nil && restore.Status.Complete != nil && *restore.Status.Complete,
+	}
+	if restore.Status != nil {
+		if restore.Status.RestoreTime != nil {
+			result["restoreTime"] = restore.Status.RestoreTime
+		}
+		if len(restore.Status.Restores) > 0 {
+			volumes := make([]map[string]interface{}, 0, len(restore.Status.Restores))
+			for _, volume := range restore.Status.Restores {
+				volumes = append(volumes, map[string]interface{}{
+					"volume":                volume.VolumeName,
+					"persistentVolumeClaim": volume.PersistentVolumeClaimName,
+				})
+			}
+			result["volumes"] = volumes
+		}
+	}
+	return newToolResultJSON(result)
+}
+
+func DeleteSnapshot(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	snapshotName, err := request.RequireString("snapshot_name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("snapshot_name parameter required: %w", err))
+	}
+
+	err = virtClient.VirtualMachineSnapshot(namespace).Delete(ctx, snapshotName, metav1.DeleteOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("deleted snapshot %s", snapshotName),
+			},
+		},
+	}, nil
+}
+
+// listSnapshotSummaries returns summaries of a page of the snapshots within
+// a namespace, oldest first within the page, optionally only those of a
+// single virtual machine
+func listSnapshotSummaries(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, name string, page pagination.Page) (map[string]interface{}, error) {
+	snapshots, err := virtClient.VirtualMachineSnapshot(namespace).List(ctx, page.ListOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	items := pagination.Window(page, snapshots.Items)
+	returned, err := pagination.Fit(len(items), func(n int) (int, error) {
+		data, err := json.MarshalIndent(snapshotSummaries(items[:n], name), "", "  ")
+		return len(data), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	continuation := page.Continuation(snapshots.ListMeta, returned, len(items))
+
+	result := map[string]interface{}{
+		"snapshots": snapshotSummaries(items[:returned], name),
+	}
+	if continuation.Cursor != "" {
+		result["nextCursor"] = continuation.Cursor
+	}
+	if continuation.Omitted > 0 {
+		result["omitted"] = continuation.Omitted
+	}
+	if continuation.Remaining != nil {
+		result["remainingItemCount"] = *continuation.Remaining
+	}
+	return result, nil
+}
+
+// snapshotSummaries summarises the snapshots oldest first, skipping those of
+// other virtual machines when a name is given
+func snapshotSummaries(items []snapshotv1beta1.VirtualMachineSnapshot, name string) []map[string]interface{} {
+	items = slices.Clone(items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp)
+	})
+	summaries := make([]map[string]interface{}, 0, len(items))
+	for i := range items {
+		if name != "" && items[i].Spec.Source.Name != name {
+			continue
+		}
+		summaries = append(summaries, output.SnapshotSummary(&items[i], nil))
+	}
+	return summaries
+}
+
+func waitForSnapshot(ctx context.Context, virtClient kubecli.KubevirtClient, snapshot *snapshotv1beta1.VirtualMachineSnapshot) (*snapshotv1beta1.VirtualMachineSnapshot, error) {
+	err := wait.PollUntilContextTimeout(ctx, snapshotPollInterval, snapshotTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := virtClient.VirtualMachineSnapshot(snapshot.Namespace).Get(ctx, snapshot.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		snapshot = current
+		if status := current.Status; status != nil {
+			if status.Phase == snapshotv1beta1.Failed {
+				return false, fmt.Errorf("snapshot %s failed", current.Name)
+			}
+			return status.ReadyToUse != nil && *status.ReadyToUse, nil
+		}
+		return false, nil
+	})
+	if wait.Interrupted(err) {
+		return nil, fmt.Errorf("timed out after %s waiting for snapshot %s to become ready", snapshotTimeout, snapshot.Name)
+	}
+	return snapshot, err
+}
+
+func waitForRestore(ctx context.Context, virtClient kubecli.KubevirtClient, restore *snapshotv1beta1.VirtualMachineRestore) (*snapshotv1beta1.VirtualMachineRestore, error) {
+	err := wait.PollUntilContextTimeout(ctx, snapshotPollInterval, snapshotTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := virtClient.VirtualMachineRestore(restore.Namespace).Get(ctx, restore.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		restore = current
+		if current.Status == nil {
+			return false, nil
+		}
+		if current.Status.Complete != nil && *current.Status.Complete {
+			return true, nil
+		}
+		if restoreFailed(current) {
+			return false, fmt.Errorf("restore %s failed%s", current.Name, restoreConditionMessage(current))
+		}
+		return false, nil
+	})
+	if wait.Interrupted(err) {
+		return nil, fmt.Errorf("timed out after %s waiting for restore %s to complete%s", snapshotTimeout, restore.Name, restoreConditionMessage(restore))
+	}
+	return restore, err
+}
+
+// restoreFailed reports whether the restore carries a true Failure
+// condition, the restore controller does not retry after setting it
+func restoreFailed(restore *snapshotv1beta1.VirtualMachineRestore) bool {
+	if restore.Status == nil {
+		return false
+	}
+	for _, cond := range restore.Status.Conditions {
+		if cond.Type == snapshotv1beta1.ConditionFailure && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreConditionMessage explains why the restore has not completed, from
+// its Failure condition or else from a Ready or Progressing condition that
+// is not true
+func restoreConditionMessage(restore *snapshotv1beta1.VirtualMachineRestore) string {
+	if restore.Status == nil {
+		return ""
+	}
+	for _, cond := range restore.Status.Conditions {
+		if cond.Type == snapshotv1beta1.ConditionFailure && cond.Status == corev1.ConditionTrue && cond.Message != "" {
+			return ": " + cond.Message
+		}
+	}
+	for _, cond := range restore.Status.Conditions {
+		if cond.Type != snapshotv1beta1.ConditionFailure && cond.Status != corev1.ConditionTrue && cond.Message != "" {
+			return ": " + cond.Message
+		}
+	}
+	return ""
+}
+
+const (
+	consistencyOffline    = "offline"
+	consistencyFilesystem = "filesystem frozen by the guest agent"
+	consistencyCrash      = "crash consistent"
+)
+
+// snapshotConsistency reports the consistency of a snapshot taken now, which
+// depends on whether the virtual machine runs and has a guest agent to freeze
+// its filesystems
+func snapshotConsistency(vmi *virtv1.VirtualMachineInstance) string {
+	switch {
+	case vmi == nil:
+		return consistencyOffline
+	case hasCondition(vmi, virtv1.VirtualMachineInstanceAgentConnected):
+		return consistencyFilesystem
+	}
+	return consistencyCrash
+}
+
+func hasCondition(vmi *virtv1.VirtualMachineInstance, conditionType virtv1.VirtualMachineInstanceConditionType) bool {
+	for _, cond := range vmi.Status.Conditions {
+		if cond.Type == conditionType && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+func SoftReboot(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+
+	vmi, err := getRunningVMI(ctx, virtClient, namespace, name)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if hasCondition(vmi, virtv1.VirtualMachineInstancePaused) {
+		return newToolResultErr(fmt.Errorf("virtual machine %s is paused, unpause it before rebooting", name))
+	}
+
+	// KubeVirt asks the guest agent to reboot when it is connected and
+	// falls back to an ACPI reboot request otherwise
+	method := "an ACPI reboot request, the guest may ignore it"
+	if hasCondition(vmi, virtv1.VirtualMachineInstanceAgentConnected) {
+		method = "the guest agent"
+	}
+	if err := virtClient.VirtualMachineInstance(namespace).SoftReboot(ctx, name); err != nil {
+		return newToolResultErr(err)
+	}
+	return newToolResultText(fmt.Sprintf("soft rebooted %s through %s", name, method))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	virtv1 "kubevirt.io/api/core/v1"
 )
 
 func Start(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -25,6 +26,31 @@ func Start(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResul
 		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
 	}
 
+	vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	runStrategy, err := vm.RunStrategy()
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("cannot %s: %w", actionStart, err))
+	}
+	message, err := lifecycleTransition(name, vm.Status.PrintableStatus, runStrategy, actionStart)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if message != "" {
+		return newToolResultText(message)
+	}
+
+	// Manual and RerunOnFailure are chosen by the user, the start
+	// subresource starts these without replacing the runStrategy
+	if runStrategy == virtv1.RunStrategyManual || runStrategy == virtv1.RunStrategyRerunOnFailure {
+		if err := virtClient.VirtualMachine(namespace).Start(ctx, name, &virtv1.StartOptions{}); err != nil {
+			return newToolResultErr(err)
+		}
+		return newToolResultText(fmt.Sprintf("started %s, keeping runStrategy %s", name, runStrategy))
+	}
+
 	// Use JSON patch to update RunStrategy to avoid conflicts
 	patchData := []byte(`[{"op": "replace", "path": "/spec/runStrategy", "value": "Always"}]`)
 	_, err = virtClient.VirtualMachine(namespace).Patch(ctx, name, types.JSONPatchType, patchData, metav1.PatchOptions{})
@@ -6,8 +6,12 @@ import (
 
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
 	"github.com/mark3labs/mcp-go/mcp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
 )
 
 func Stop(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -25,6 +29,14 @@ func Stop(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult
 		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
 	}
 
+	message, err := lifecyclePreflight(ctx, virtClient, namespace, name, actionStop)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if message != "" {
+		return newToolResultText(message)
+	}
+
 	// Use JSON patch to update RunStrategy to avoid conflicts
 	patchData := []byte(`[{"op": "replace", "path": "/spec/runStrategy", "value": "Halted"}]`)
 	_, err = virtClient.VirtualMachine(namespace).Patch(ctx, name, types.JSONPatchType, patchData, metav1.PatchOptions{})
@@ -41,3 +53,83 @@ func Stop(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult
 		},
 	}, nil
 }
+
+func ForceStop(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+
+	_, err = virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+	running := err == nil
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return newToolResultErr(err)
+	}
+	if !running {
+		vm, err := virtClient.VirtualMachine(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return newToolResultErr(err)
+		}
+		runStrategy, err := vm.RunStrategy()
+		if err != nil {
+			return newToolResultErr(fmt.Errorf("cannot force stop: %w", err))
+		}
+		if message := forceStopNoop(name, runStrategy); message != "" {
+			return newToolResultText(message)
+		}
+	}
+
+	// A grace period of zero kills the instance without waiting for the guest
+	// to shut down, the stop subresource also halts the run strategy
+	if err := virtClient.VirtualMachine(namespace).Stop(ctx, name, &virtv1.StopOptions{GracePeriod: new(int64)}); err != nil {
+		return newToolResultErr(err)
+	}
+	if !running {
+		return newToolResultText(fmt.Sprintf("stopped %s (was not running)", name))
+	}
+
+	gone, err := waitForVMIGone(ctx, virtClient, namespace, name)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	if !gone {
+		return newToolResultText(fmt.Sprintf("force stop of %s requested, the instance has not terminated yet", name))
+	}
+	return newToolResultText(fmt.Sprintf("force stopped %s, the instance was terminated without a guest shutdown", name))
+}
+
+// forceStopNoop returns why a virtual machine without an instance needs no
+// force stop, the stop subresource rejects it as not running. Empty means
+// the run strategy is about to start an instance which has to be halted.
+func forceStopNoop(name string, runStrategy virtv1.VirtualMachineRunStrategy) string {
+	switch runStrategy {
+	case virtv1.RunStrategyHalted, virtv1.RunStrategyManual:
+		return fmt.Sprintf("VM %s is already stopped", name)
+	}
+	return ""
+}
+
+// waitForVMIGone polls until the VMI of a virtual machine has been removed,
+// reporting false when it is still present after the timeout
+func waitForVMIGone(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, name string) (bool, error) {
+	err := wait.PollUntilContextTimeout(ctx, powerPollInterval, powerTimeout, true, func(ctx context.Context) (bool, error) {
+		_, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if wait.Interrupted(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
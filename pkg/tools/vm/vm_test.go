@@ -7,6 +7,11 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+	exportv1beta1 "kubevirt.io/api/export/v1beta1"
+	snapshotv1beta1 "kubevirt.io/api/snapshot/v1beta1"
+
 	"github.com/lyarwood/kubevirt-mcp-server/pkg/tools/vm"
 )
 
@@ -44,9 +49,51 @@ var _ = Describe("VM", func() {
 				Expect(result.IsError).To(BeTrue())
 				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
 			})
+
+			It("should return an error for an out of range limit", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-namespace",
+					"limit":     float64(0),
+				}
+
+				result, err := vm.List(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("limit must be between"))
+			})
+
+			It("should return an error when all_namespaces and namespaces are both given", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"all_namespaces": true,
+					"namespaces":     []interface{}{"ns-a", "ns-b"},
+				}
+
+				result, err := vm.List(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("mutually exclusive"))
+			})
 		})
 
 		Context("when given valid arguments", func() {
+			It("should accept a list of namespaces instead of namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespaces": []interface{}{"ns-a", "ns-b"},
+				}
+
+				// This will fail due to no KubeVirt cluster, but we're testing the argument parsing
+				result, err := vm.List(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+
 			It("should accept valid namespace parameter", func() {
 				request := mcp.CallToolRequest{}
 				request.Params.Arguments = map[string]interface{}{
@@ -543,6 +590,21 @@ var _ = Describe("VM", func() {
 				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
 			})
 
+			It("should return an error for an unsupported format", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "default",
+					"name":      "test-vm",
+					"format":    "xml",
+				}
+
+				result, err := vm.GetStatus(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported format"))
+			})
+
 			It("should return an error for missing name", func() {
 				request := mcp.CallToolRequest{}
 				request.Params.Arguments = map[string]interface{}{
@@ -798,4 +860,2284 @@ var _ = Describe("VM", func() {
 			})
 		})
 	})
+
+	Describe("Migrate", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name": "test-vm",
+				}
+
+				result, err := vm.Migrate(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error for missing name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.Migrate(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name parameter required"))
+			})
+
+			It("should return an error for a non-object node selector", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":           "test-ns",
+					"name":                "test-vm",
+					"added_node_selector": "node02",
+				}
+
+				result, err := vm.Migrate(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("added_node_selector parameter must be an object of strings"))
+			})
+
+			It("should return an error for a non-string node selector value", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":           "test-ns",
+					"name":                "test-vm",
+					"added_node_selector": map[string]interface{}{"zone": 1},
+				}
+
+				result, err := vm.Migrate(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring(`key "zone" must have a string value`))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace and name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-namespace",
+					"name":      "test-vm",
+				}
+
+				// This will fail due to no KubeVirt cluster, but we're testing the argument parsing
+				result, err := vm.Migrate(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("GetMigrationStatus", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name": "test-vm",
+				}
+
+				result, err := vm.GetMigrationStatus(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error for missing name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.GetMigrationStatus(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace and name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-namespace",
+					"name":      "test-vm",
+				}
+
+				// This will fail due to no KubeVirt cluster, but we're testing the argument parsing
+				result, err := vm.GetMigrationStatus(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("CancelMigration", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name": "test-vm",
+				}
+
+				result, err := vm.CancelMigration(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error for missing name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.CancelMigration(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace and name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-namespace",
+					"name":      "test-vm",
+				}
+
+				// This will fail due to no KubeVirt cluster, but we're testing the argument parsing
+				result, err := vm.CancelMigration(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("AddVolume", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":   "test-ns",
+					"volume_name": "data",
+				}
+
+				result, err := vm.AddVolume(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name parameter required"))
+			})
+
+			It("should return an error for missing volume_name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+				}
+
+				result, err := vm.AddVolume(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("volume_name parameter required"))
+			})
+
+			It("should return an error for an unsupported source", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":   "test-ns",
+					"name":        "test-vm",
+					"volume_name": "data",
+					"source":      "nfs",
+				}
+
+				result, err := vm.AddVolume(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported source"))
+			})
+
+			It("should return an error for an unsupported bus", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":   "test-ns",
+					"name":        "test-vm",
+					"volume_name": "data",
+					"bus":         "sata",
+				}
+
+				result, err := vm.AddVolume(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported bus"))
+			})
+
+			It("should return an error for a lun on the virtio bus", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":   "test-ns",
+					"name":        "test-vm",
+					"volume_name": "data",
+					"disk_type":   "lun",
+					"bus":         "virtio",
+				}
+
+				result, err := vm.AddVolume(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("lun disks must use the scsi bus"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a datavolume hotplugged as a lun", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":   "test-ns",
+					"name":        "test-vm",
+					"volume_name": "data",
+					"source":      "datavolume",
+					"disk_type":   "lun",
+					"persist":     true,
+				}
+
+				result, err := vm.AddVolume(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("RemoveVolume", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name":        "test-vm",
+					"volume_name": "data",
+				}
+
+				result, err := vm.RemoveVolume(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error for missing volume_name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+				}
+
+				result, err := vm.RemoveVolume(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("volume_name parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace, name and volume_name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":   "test-ns",
+					"name":        "test-vm",
+					"volume_name": "data",
+				}
+
+				result, err := vm.RemoveVolume(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("CreateSnapshot", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name": "test-vm",
+				}
+
+				result, err := vm.CreateSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error for missing name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.CreateSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a crash consistent snapshot request", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":     "test-ns",
+					"name":          "test-vm",
+					"snapshot_name": "test-snapshot",
+					"freeze":        false,
+				}
+
+				result, err := vm.CreateSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("ListSnapshots", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name": "test-vm",
+				}
+
+				result, err := vm.ListSnapshots(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error for an out of range limit", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"limit":     float64(0),
+				}
+
+				result, err := vm.ListSnapshots(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("limit must be between 1 and 500"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a namespace without a virtual machine name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.ListSnapshots(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("GetSnapshot", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing snapshot_name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.GetSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("snapshot_name parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace and snapshot_name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":     "test-ns",
+					"snapshot_name": "test-snapshot",
+				}
+
+				result, err := vm.GetSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("RestoreSnapshot", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":     "test-ns",
+					"snapshot_name": "test-snapshot",
+				}
+
+				result, err := vm.RestoreSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name parameter required"))
+			})
+
+			It("should return an error for missing snapshot_name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+				}
+
+				result, err := vm.RestoreSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("snapshot_name parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace, name and snapshot_name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":     "test-ns",
+					"name":          "test-vm",
+					"snapshot_name": "test-snapshot",
+				}
+
+				result, err := vm.RestoreSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("DeleteSnapshot", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"snapshot_name": "test-snapshot",
+				}
+
+				result, err := vm.DeleteSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error for missing snapshot_name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.DeleteSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("snapshot_name parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace and snapshot_name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":     "test-ns",
+					"snapshot_name": "test-snapshot",
+				}
+
+				result, err := vm.DeleteSnapshot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("Clone", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name": "test-vm",
+				}
+
+				result, err := vm.Clone(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error when no source is given", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.Clone(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name or source_snapshot parameter required"))
+			})
+
+			It("should return an error when both sources are given", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":       "test-ns",
+					"name":            "test-vm",
+					"source_snapshot": "test-snapshot",
+				}
+
+				result, err := vm.Clone(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("mutually exclusive"))
+			})
+
+			It("should return an error for non-string MAC addresses", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":         "test-ns",
+					"name":              "test-vm",
+					"new_mac_addresses": map[string]interface{}{"default": 42},
+				}
+
+				result, err := vm.Clone(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("new_mac_addresses key"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a snapshot source with filters and overrides", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":         "test-ns",
+					"source_snapshot":   "test-snapshot",
+					"target_name":       "test-clone",
+					"label_filters":     []interface{}{"*", "!example.com/*"},
+					"new_mac_addresses": map[string]interface{}{"default": "02:00:00:00:00:01"},
+					"new_smbios_serial": "serial-1",
+				}
+
+				result, err := vm.Clone(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("Export", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name": "test-vm",
+				}
+
+				result, err := vm.Export(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error for missing name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.Export(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name parameter required"))
+			})
+
+			It("should return an error for an unsupported source kind", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":   "test-ns",
+					"name":        "test-vm",
+					"source_kind": "datavolume",
+				}
+
+				result, err := vm.Export(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported source_kind"))
+			})
+
+			It("should return an error for an invalid ttl", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+					"ttl":       "soon",
+				}
+
+				result, err := vm.Export(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("ttl must be a positive duration"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a pvc export with a ttl", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":   "test-ns",
+					"name":        "test-pvc",
+					"source_kind": "pvc",
+					"ttl":         "30m",
+				}
+
+				result, err := vm.Export(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("SetInstancetype", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error when instancetype is missing", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+				}
+
+				result, err := vm.SetInstancetype(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("instancetype parameter required"))
+			})
+
+			It("should return an error for an unsupported kind", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":    "test-ns",
+					"name":         "test-vm",
+					"instancetype": "u1.medium",
+					"kind":         "VirtualMachinePreference",
+				}
+
+				result, err := vm.SetInstancetype(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported kind"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a namespaced instance type with restart", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":    "test-ns",
+					"name":         "test-vm",
+					"instancetype": "u1.medium",
+					"kind":         "VirtualMachineInstancetype",
+					"restart":      true,
+				}
+
+				result, err := vm.SetInstancetype(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("SetPreference", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error when preference is missing", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+				}
+
+				result, err := vm.SetPreference(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("preference parameter required"))
+			})
+
+			It("should return an error for an unsupported kind", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":  "test-ns",
+					"name":       "test-vm",
+					"preference": "fedora",
+					"kind":       "VirtualMachineInstancetype",
+				}
+
+				result, err := vm.SetPreference(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported kind"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a cluster preference", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":  "test-ns",
+					"name":       "test-vm",
+					"preference": "fedora",
+				}
+
+				result, err := vm.SetPreference(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("Resize", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error when neither sockets nor memory is given", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+				}
+
+				result, err := vm.Resize(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("sockets or memory parameter required"))
+			})
+
+			It("should return an error for fractional sockets", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+					"sockets":   1.5,
+				}
+
+				result, err := vm.Resize(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("sockets parameter must be a positive integer"))
+			})
+
+			It("should return an error for an invalid memory quantity", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+					"memory":    "lots",
+				}
+
+				result, err := vm.Resize(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("memory must be a positive quantity"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept sockets and memory", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+					"sockets":   float64(4),
+					"memory":    "4Gi",
+				}
+
+				result, err := vm.Resize(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("AddInterface", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error when interface_name is missing", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":                     "test-ns",
+					"name":                          "test-vm",
+					"network_attachment_definition": "bridge-net",
+				}
+
+				result, err := vm.AddInterface(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("interface_name parameter required"))
+			})
+
+			It("should return an error when network_attachment_definition is missing", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"interface_name": "net1",
+				}
+
+				result, err := vm.AddInterface(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("network_attachment_definition parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a namespaced network attachment definition", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":                     "test-ns",
+					"name":                          "test-vm",
+					"interface_name":                "net1",
+					"network_attachment_definition": "other-ns/bridge-net",
+					"mac_address":                   "02:00:00:00:00:01",
+				}
+
+				result, err := vm.AddInterface(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("RemoveInterface", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error when interface_name is missing", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+				}
+
+				result, err := vm.RemoveInterface(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("interface_name parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept an interface name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"interface_name": "net1",
+				}
+
+				result, err := vm.RemoveInterface(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("Restart force options", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error when grace_period is set without force", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":    "test-ns",
+					"name":         "test-vm",
+					"grace_period": float64(0),
+				}
+
+				result, err := vm.Restart(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("grace_period can only be set together with force"))
+			})
+
+			It("should return an error for a non-zero forced grace_period", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":    "test-ns",
+					"name":         "test-vm",
+					"force":        true,
+					"grace_period": float64(30),
+				}
+
+				result, err := vm.Restart(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("only supports a grace_period of 0"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a forced restart", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":    "test-ns",
+					"name":         "test-vm",
+					"force":        true,
+					"grace_period": float64(0),
+				}
+
+				result, err := vm.Restart(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("SoftReboot", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.SoftReboot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace and name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+				}
+
+				result, err := vm.SoftReboot(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("ForceStop", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name": "test-vm",
+				}
+
+				result, err := vm.ForceStop(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace and name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+				}
+
+				result, err := vm.ForceStop(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("Create with cloud-init", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for invalid cloud-config YAML", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"container_disk": "fedora",
+					"user_data":      "#cloud-config\nusers: [unterminated",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("user_data is not valid cloud-config YAML"))
+			})
+
+			It("should return an error for user data without a header", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"container_disk": "fedora",
+					"user_data":      "password: fedora",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("user_data must start with #cloud-config"))
+			})
+
+			It("should return an error for network data without a version", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"container_disk": "fedora",
+					"network_data":   "ethernets: {}",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("network_data must set a network config version"))
+			})
+
+			It("should return an error when cloud_init_secret is combined with user_data", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":         "test-ns",
+					"name":              "test-vm",
+					"container_disk":    "fedora",
+					"cloud_init_secret": "my-userdata",
+					"user_data":         "#cloud-config\n",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("mutually exclusive"))
+			})
+
+			It("should return an error for a malformed SSH key", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":           "test-ns",
+					"name":                "test-vm",
+					"container_disk":      "fedora",
+					"ssh_authorized_keys": []interface{}{"not-a-key"},
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("is not an SSH public key"))
+			})
+
+			It("should return an error for an unsupported datasource", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":             "test-ns",
+					"name":                  "test-vm",
+					"container_disk":        "fedora",
+					"user_data":             "#!/bin/sh\necho hi",
+					"cloud_init_datasource": "ec2",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported cloud_init_datasource"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept cloud-config user data and SSH keys", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":             "test-ns",
+					"name":                  "test-vm",
+					"container_disk":        "fedora",
+					"user_data":             "#cloud-config\npassword: fedora\nchpasswd: { expire: False }\n",
+					"network_data":          "version: 2\nethernets:\n  eth0:\n    dhcp4: true\n",
+					"ssh_authorized_keys":   []interface{}{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIExample user@host"},
+					"cloud_init_datasource": "configdrive",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("Create with a persistent root disk", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error when container_disk and data_source are combined", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"container_disk": "fedora",
+					"data_source":    "os-images/fedora",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("mutually exclusive"))
+			})
+
+			It("should return an error when data_source and disk_image_url are combined", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"data_source":    "fedora",
+					"disk_image_url": "https://example.com/disk.qcow2",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("data_source and disk_image_url parameters are mutually exclusive"))
+			})
+
+			It("should return an error for an unsupported disk_image_url scheme", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"disk_image_url": "ftp://example.com/disk.qcow2",
+					"disk_size":      "10Gi",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("disk_image_url must be a docker:// registry or http(s):// URL"))
+			})
+
+			It("should return an error when importing without disk_size", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"disk_image_url": "docker://quay.io/containerdisks/fedora:latest",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("disk_size parameter required"))
+			})
+
+			It("should return an error for an invalid volume_mode", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":   "test-ns",
+					"name":        "test-vm",
+					"data_source": "fedora",
+					"volume_mode": "Raw",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported volume_mode"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a data source with storage options", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":     "test-ns",
+					"name":          "test-vm",
+					"data_source":   "openshift-virtualization-os-images/fedora",
+					"disk_size":     "30Gi",
+					"storage_class": "local",
+					"access_mode":   "ReadWriteMany",
+					"volume_mode":   "Block",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("CreateFromManifest", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error when manifest is missing", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.CreateFromManifest(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("manifest parameter required"))
+			})
+
+			It("should return an error for a document that is not YAML", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"manifest":  "{not: [valid",
+				}
+
+				result, err := vm.CreateFromManifest(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("not a valid YAML or JSON document"))
+			})
+
+			It("should report unknown fields", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"manifest":  "apiVersion: kubevirt.io/v1\nkind: VirtualMachine\nmetadata:\n  name: test-vm\nspec:\n  runStrategy: Halted\n  tempalte: {}\n",
+				}
+
+				result, err := vm.CreateFromManifest(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unknown field \"spec.tempalte\""))
+			})
+
+			It("should report every problem of the manifest", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"manifest":  "apiVersion: v1\nkind: Pod\nmetadata:\n  name: test-vm\n  namespace: other-ns\n",
+				}
+
+				result, err := vm.CreateFromManifest(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("metadata.namespace: \"other-ns\" does not match"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a valid manifest", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"manifest":  "apiVersion: kubevirt.io/v1\nkind: VirtualMachine\nmetadata:\n  name: test-vm\nspec:\n  runStrategy: Halted\n  template:\n    spec:\n      domain:\n        devices: {}\n",
+					"dry_run":   true,
+				}
+
+				result, err := vm.CreateFromManifest(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("manifest failed validation"))
+			})
+		})
+	})
+
+	Describe("Create with networking", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for an unsupported pod network binding", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":           "test-ns",
+					"name":                "test-vm",
+					"container_disk":      "fedora",
+					"pod_network_binding": "slirp",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported pod_network_binding"))
+			})
+
+			It("should return an error without any network when the pod network is disabled", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":           "test-ns",
+					"name":                "test-vm",
+					"container_disk":      "fedora",
+					"pod_network_binding": "none",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("requires at least one network"))
+			})
+
+			It("should return an error for a MAC address of an unknown interface", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"container_disk": "fedora",
+					"mac_addresses":  map[string]interface{}{"net1": "02:00:00:00:00:01"},
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("does not match any interface"))
+			})
+
+			It("should return an error for an invalid MAC address", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"container_disk": "fedora",
+					"mac_addresses":  map[string]interface{}{"default": "not-a-mac"},
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("is not a valid MAC address"))
+			})
+
+			It("should return an error for an invalid port", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"container_disk": "fedora",
+					"expose_ports":   []interface{}{float64(70000)},
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("is not a port number"))
+			})
+
+			It("should return an error when exposing ports without the pod network", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":           "test-ns",
+					"name":                "test-vm",
+					"container_disk":      "fedora",
+					"pod_network_binding": "none",
+					"networks":            []interface{}{"bridge-net"},
+					"expose_ports":        []interface{}{float64(22)},
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("expose_ports requires the pod network"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept secondary networks and exposed ports", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":           "test-ns",
+					"name":                "test-vm",
+					"container_disk":      "fedora",
+					"pod_network_binding": "bridge",
+					"networks":            []interface{}{"bridge-net"},
+					"mac_addresses":       map[string]interface{}{"net1": "02:00:00:00:00:01"},
+					"interface_model":     "virtio",
+					"expose_ports":        []interface{}{float64(22), float64(3389)},
+					"service_type":        "NodePort",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("Create with instancetype inference", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error when infer_instancetype and instancetype are combined", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":          "test-ns",
+					"name":               "test-vm",
+					"container_disk":     "fedora",
+					"instancetype":       "u1.medium",
+					"infer_instancetype": true,
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("infer_instancetype and instancetype parameters are mutually exclusive"))
+			})
+
+			It("should return an error when infer_preference and preference are combined", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":        "test-ns",
+					"name":             "test-vm",
+					"container_disk":   "fedora",
+					"preference":       "fedora",
+					"infer_preference": true,
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("infer_preference and preference parameters are mutually exclusive"))
+			})
+
+			It("should return an error when inferring from an http disk_image_url", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":          "test-ns",
+					"name":               "test-vm",
+					"disk_image_url":     "https://example.com/disk.qcow2",
+					"disk_size":          "10Gi",
+					"infer_instancetype": true,
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("cannot infer instance type or preference from https://example.com/disk.qcow2"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept infer_instancetype and infer_preference with a data_source", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":          "test-ns",
+					"name":               "test-vm",
+					"data_source":        "os-images/fedora",
+					"infer_instancetype": true,
+					"infer_preference":   true,
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("mutually exclusive"))
+			})
+		})
+	})
+
+	Describe("Create with an architecture", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for an unsupported architecture", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"container_disk": "fedora",
+					"architecture":   "ppc64le",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported architecture \"ppc64le\""))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept a supported architecture", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"name":           "test-vm",
+					"container_disk": "fedora",
+					"architecture":   "arm64",
+				}
+
+				result, err := vm.Create(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("unsupported architecture"))
+			})
+		})
+	})
+
+	Describe("BulkAction", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error when namespace is missing", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"action": "stop",
+					"names":  []interface{}{"vm-a", "vm-b"},
+				}
+
+				result, err := vm.BulkAction(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error when action is missing", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"names":     []interface{}{"vm-a", "vm-b"},
+				}
+
+				result, err := vm.BulkAction(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("action parameter required"))
+			})
+
+			It("should return an error for an unsupported action", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"action":    "migrate",
+					"names":     []interface{}{"vm-a", "vm-b"},
+				}
+
+				result, err := vm.BulkAction(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported action \"migrate\""))
+			})
+
+			It("should return an error when neither label_selector nor names is given", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"action":    "stop",
+				}
+
+				result, err := vm.BulkAction(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("label_selector or names parameter required"))
+			})
+
+			It("should return an error when label_selector and names are combined", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"action":         "stop",
+					"names":          []interface{}{"vm-a", "vm-b"},
+					"label_selector": "env=dev",
+				}
+
+				result, err := vm.BulkAction(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("label_selector and names parameters are mutually exclusive"))
+			})
+
+			It("should return an error for an invalid label_selector", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":      "test-ns",
+					"action":         "stop",
+					"label_selector": "env in (dev",
+				}
+
+				result, err := vm.BulkAction(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("invalid label_selector"))
+			})
+
+			It("should return an error for an out of range concurrency", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":   "test-ns",
+					"action":      "stop",
+					"names":       []interface{}{"vm-a", "vm-b"},
+					"concurrency": float64(50),
+				}
+
+				result, err := vm.BulkAction(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("concurrency must be a whole number between 1 and 20"))
+			})
+
+			It("should require confirm above the threshold", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"action":    "stop",
+					"names":     []interface{}{"vm-0", "vm-1", "vm-2", "vm-3", "vm-4", "vm-5", "vm-6", "vm-7", "vm-8", "vm-9", "vm-10"},
+				}
+
+				result, err := vm.BulkAction(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("call again with confirm set to true"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should report a result per virtual machine", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"action":    "stop",
+					"names":     []interface{}{"vm-a", "vm-b", "vm-a"},
+				}
+
+				result, err := vm.BulkAction(ctx, request)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.IsError).To(BeFalse())
+				text := result.Content[0].(mcp.TextContent).Text
+				Expect(text).To(ContainSubstring(`"total": 2`))
+				Expect(text).To(ContainSubstring(`"name": "vm-a"`))
+				Expect(text).To(ContainSubstring(`"name": "vm-b"`))
+			})
+		})
+	})
+
+	Describe("Network", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name": "test-vm",
+				}
+
+				result, err := vm.Network(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error for missing name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.Network(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name parameter required"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace and name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+				}
+
+				result, err := vm.Network(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("SerialOutput", func() {
+		Context("when given invalid arguments", func() {
+			It("should return an error for missing namespace", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"name": "test-vm",
+				}
+
+				result, err := vm.SerialOutput(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("namespace parameter required"))
+			})
+
+			It("should return an error for missing name", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+				}
+
+				result, err := vm.SerialOutput(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("name parameter required"))
+			})
+
+			It("should return an error for an unsupported source", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+					"source":    "vnc",
+				}
+
+				result, err := vm.SerialOutput(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("unsupported source \"vnc\""))
+			})
+
+			It("should return an error for an out of range duration", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":        "test-ns",
+					"name":             "test-vm",
+					"duration_seconds": float64(120),
+				}
+
+				result, err := vm.SerialOutput(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("duration_seconds must be a whole number between 1 and 60"))
+			})
+
+			It("should return an error for an invalid max_bytes", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace": "test-ns",
+					"name":      "test-vm",
+					"max_bytes": float64(0),
+				}
+
+				result, err := vm.SerialOutput(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).To(ContainSubstring("max_bytes must be a whole number between 1 and 1048576"))
+			})
+		})
+
+		Context("when given valid arguments", func() {
+			It("should accept valid namespace and name parameters", func() {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = map[string]interface{}{
+					"namespace":        "test-ns",
+					"name":             "test-vm",
+					"duration_seconds": float64(1),
+					"source":           "log",
+				}
+
+				result, err := vm.SerialOutput(ctx, request)
+
+				Expect(err).To(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(mcp.TextContent).Text).NotTo(ContainSubstring("parameter required"))
+			})
+		})
+	})
+
+	Describe("WithMultusInterface", func() {
+		It("should keep the implicit pod network of a VM without interfaces", func() {
+			spec := &virtv1.VirtualMachineInstanceSpec{}
+
+			interfaces, networks := vm.WithMultusInterface(spec, virtv1.DefaultMasqueradeNetworkInterface(), "net1", "bridge-net", "")
+
+			Expect(interfaces).To(HaveLen(2))
+			Expect(interfaces[0].Name).To(Equal("default"))
+			Expect(interfaces[0].Masquerade).NotTo(BeNil())
+			Expect(interfaces[1].Name).To(Equal("net1"))
+			Expect(interfaces[1].Bridge).NotTo(BeNil())
+			Expect(networks).To(HaveLen(2))
+			Expect(networks[0].Pod).NotTo(BeNil())
+			Expect(networks[1].Multus.NetworkName).To(Equal("bridge-net"))
+		})
+
+		It("should not add the pod network when autoattachPodInterface is false", func() {
+			autoattach := false
+			spec := &virtv1.VirtualMachineInstanceSpec{}
+			spec.Domain.Devices.AutoattachPodInterface = &autoattach
+
+			interfaces, networks := vm.WithMultusInterface(spec, virtv1.DefaultMasqueradeNetworkInterface(), "net1", "bridge-net", "")
+
+			Expect(interfaces).To(HaveLen(1))
+			Expect(networks).To(HaveLen(1))
+			Expect(networks[0].Multus).NotTo(BeNil())
+		})
+
+		It("should append to existing interfaces without changing them", func() {
+			spec := &virtv1.VirtualMachineInstanceSpec{
+				Networks: []virtv1.Network{*virtv1.DefaultPodNetwork()},
+			}
+			spec.Domain.Devices.Interfaces = []virtv1.Interface{*virtv1.DefaultBridgeNetworkInterface()}
+
+			interfaces, networks := vm.WithMultusInterface(spec, nil, "net1", "ns/bridge-net", "02:00:00:00:00:01")
+
+			Expect(interfaces).To(HaveLen(2))
+			Expect(interfaces[0].Bridge).NotTo(BeNil())
+			Expect(interfaces[1].MacAddress).To(Equal("02:00:00:00:00:01"))
+			Expect(networks).To(HaveLen(2))
+			Expect(spec.Domain.Devices.Interfaces).To(HaveLen(1))
+		})
+	})
+
+	Describe("DefaultPodInterface", func() {
+		It("should use masquerade when the cluster sets no default", func() {
+			iface, err := vm.DefaultPodInterface(&virtv1.KubeVirtConfiguration{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(iface.Masquerade).NotTo(BeNil())
+		})
+
+		It("should use the bridge binding when the cluster default is bridge", func() {
+			iface, err := vm.DefaultPodInterface(&virtv1.KubeVirtConfiguration{
+				NetworkConfiguration: &virtv1.NetworkConfiguration{NetworkInterface: "bridge"},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(iface.Bridge).NotTo(BeNil())
+		})
+
+		It("should refuse an unsupported default binding", func() {
+			_, err := vm.DefaultPodInterface(&virtv1.KubeVirtConfiguration{
+				NetworkConfiguration: &virtv1.NetworkConfiguration{NetworkInterface: "slirp"},
+			})
+
+			Expect(err).To(MatchError(ContainSubstring("declare the pod interface")))
+		})
+
+		It("should refuse when the configuration cannot be read", func() {
+			_, err := vm.DefaultPodInterface(nil)
+
+			Expect(err).To(MatchError(ContainSubstring("declare the pod interface")))
+		})
+	})
+
+	Describe("DescribeDisk", func() {
+		volumes := []virtv1.Volume{{
+			Name: "rootdisk",
+			VolumeSource: virtv1.VolumeSource{
+				DataVolume: &virtv1.DataVolumeSource{Name: "test-vm-rootdisk"},
+			},
+		}}
+
+		DescribeTable("should report the device, bus and read-only state",
+			func(disk virtv1.Disk, device string, bus virtv1.DiskBus, readOnly bool) {
+				disk.Name = "rootdisk"
+				info := vm.DescribeDisk(disk, volumes, nil, true)
+
+				Expect(info.Device).To(Equal(device))
+				Expect(info.Bus).To(Equal(bus))
+				Expect(info.ReadOnly).To(Equal(readOnly))
+			},
+			Entry("disk", virtv1.Disk{DiskDevice: virtv1.DiskDevice{Disk: &virtv1.DiskTarget{Bus: virtv1.DiskBusVirtio}}}, "disk", virtv1.DiskBusVirtio, false),
+			Entry("cdrom, read-only by default", virtv1.Disk{DiskDevice: virtv1.DiskDevice{CDRom: &virtv1.CDRomTarget{Bus: virtv1.DiskBusSATA}}}, "cdrom", virtv1.DiskBusSATA, true),
+			Entry("cdrom made writable", virtv1.Disk{DiskDevice: virtv1.DiskDevice{CDRom: &virtv1.CDRomTarget{ReadOnly: new(bool)}}}, "cdrom", virtv1.DiskBus(""), false),
+			Entry("lun", virtv1.Disk{DiskDevice: virtv1.DiskDevice{LUN: &virtv1.LunTarget{Bus: virtv1.DiskBusSCSI, ReadOnly: true}}}, "lun", virtv1.DiskBusSCSI, true),
+			Entry("no device defaults to disk", virtv1.Disk{}, "disk", virtv1.DiskBus(""), false),
+		)
+
+		It("should warn when no volume backs the disk", func() {
+			info := vm.DescribeDisk(virtv1.Disk{Name: "orphan"}, volumes, nil, true)
+
+			Expect(info.Source).To(BeNil())
+			Expect(info.Warnings).To(ConsistOf(ContainSubstring("no volume named orphan")))
+		})
+
+		It("should mark hotpluggable volumes as hotplugged", func() {
+			hotplug := []virtv1.Volume{{
+				Name: "data",
+				VolumeSource: virtv1.VolumeSource{
+					PersistentVolumeClaim: &virtv1.PersistentVolumeClaimVolumeSource{Hotpluggable: true},
+				},
+			}}
+
+			info := vm.DescribeDisk(virtv1.Disk{Name: "data"}, hotplug, nil, true)
+
+			Expect(info.Hotplugged).To(BeTrue())
+		})
+	})
+
+	Describe("DescribeVolumeSource", func() {
+		DescribeTable("should report the type and backing object",
+			func(source virtv1.VolumeSource, expectedType, image, claimName, dataVolume string) {
+				info := vm.DescribeVolumeSource(virtv1.Volume{Name: "volume", VolumeSource: source})
+
+				Expect(info.Type).To(Equal(expectedType))
+				Expect(info.Image).To(Equal(image))
+				Expect(info.ClaimName).To(Equal(claimName))
+				Expect(info.DataVolume).To(Equal(dataVolume))
+			},
+			Entry("container disk", virtv1.VolumeSource{ContainerDisk: &virtv1.ContainerDiskSource{Image: "quay.io/containerdisks/fedora"}}, "containerDisk", "quay.io/containerdisks/fedora", "", ""),
+			Entry("data volume backed by its claim", virtv1.VolumeSource{DataVolume: &virtv1.DataVolumeSource{Name: "root"}}, "dataVolume", "", "root", "root"),
+			Entry("cloud-init", virtv1.VolumeSource{CloudInitNoCloud: &virtv1.CloudInitNoCloudSource{UserData: "#cloud-config"}}, "cloudInitNoCloud", "", "", ""),
+			Entry("empty source", virtv1.VolumeSource{}, "unknown", "", "", ""),
+		)
+	})
+
+	Describe("DescribeDisks", func() {
+		It("should list disks hotplugged into the running instance only as not persisted", func() {
+			machine := &virtv1.VirtualMachine{
+				Spec: virtv1.VirtualMachineSpec{
+					Template: &virtv1.VirtualMachineInstanceTemplateSpec{},
+				},
+			}
+			machine.Spec.Template.Spec.Domain.Devices.Disks = []virtv1.Disk{{Name: "rootdisk"}}
+			vmi := &virtv1.VirtualMachineInstance{}
+			vmi.Spec.Domain.Devices.Disks = []virtv1.Disk{{Name: "rootdisk"}, {Name: "scratch"}}
+			vmi.Spec.Volumes = []virtv1.Volume{{
+				Name: "scratch",
+				VolumeSource: virtv1.VolumeSource{
+					PersistentVolumeClaim: &virtv1.PersistentVolumeClaimVolumeSource{Hotpluggable: true},
+				},
+			}}
+			vmi.Status.VolumeStatus = []virtv1.VolumeStatus{{
+				Name:          "scratch",
+				HotplugVolume: &virtv1.HotplugVolumeStatus{},
+			}}
+
+			disks := vm.DescribeDisks(machine, vmi)
+
+			Expect(disks).To(HaveLen(2))
+			Expect(disks[0].Name).To(Equal("rootdisk"))
+			Expect(disks[0].Persisted).To(BeTrue())
+			Expect(disks[1].Name).To(Equal("scratch"))
+			Expect(disks[1].Persisted).To(BeFalse())
+			Expect(disks[1].Hotplugged).To(BeTrue())
+			Expect(disks[1].Status).NotTo(BeNil())
+		})
+	})
+
+	Describe("LifecycleTransition", func() {
+		DescribeTable("checks the action against the power state",
+			func(action vm.LifecycleAction, status virtv1.VirtualMachinePrintableStatus, runStrategy virtv1.VirtualMachineRunStrategy, message, failure string) {
+				result, err := vm.LifecycleTransition("test-vm", status, runStrategy, action)
+				if failure != "" {
+					Expect(err).To(MatchError(ContainSubstring(failure)))
+					return
+				}
+				Expect(err).NotTo(HaveOccurred())
+				if message == "" {
+					Expect(result).To(BeEmpty())
+				} else {
+					Expect(result).To(ContainSubstring(message))
+				}
+			},
+			Entry("start a stopped VM", vm.ActionStart, virtv1.VirtualMachineStatusStopped, virtv1.RunStrategyHalted, "", ""),
+			Entry("start a stopped Manual VM", vm.ActionStart, virtv1.VirtualMachineStatusStopped, virtv1.RunStrategyManual, "", ""),
+			Entry("start a running VM", vm.ActionStart, virtv1.VirtualMachineStatusRunning, virtv1.RunStrategyAlways, "already running", ""),
+			Entry("start a paused VM", vm.ActionStart, virtv1.VirtualMachineStatusPaused, virtv1.RunStrategyAlways, "", "use unpause_vm"),
+			Entry("start a VM without status", vm.ActionStart, virtv1.VirtualMachinePrintableStatus(""), virtv1.RunStrategyHalted, "", ""),
+			Entry("start a failing Always VM", vm.ActionStart, virtv1.VirtualMachineStatusCrashLoopBackOff, virtv1.RunStrategyAlways, "already set to run", ""),
+			Entry("stop a stopped VM", vm.ActionStop, virtv1.VirtualMachineStatusStopped, virtv1.RunStrategyHalted, "already stopped", ""),
+			Entry("stop a running VM", vm.ActionStop, virtv1.VirtualMachineStatusRunning, virtv1.RunStrategyAlways, "", ""),
+			Entry("stop a running Manual VM", vm.ActionStop, virtv1.VirtualMachineStatusRunning, virtv1.RunStrategyManual, "", ""),
+			Entry("stop a paused VM", vm.ActionStop, virtv1.VirtualMachineStatusPaused, virtv1.RunStrategyAlways, "", ""),
+			Entry("stop a Halted VM without status", vm.ActionStop, virtv1.VirtualMachinePrintableStatus(""), virtv1.RunStrategyHalted, "already stopped", ""),
+			Entry("stop an Always VM without status", vm.ActionStop, virtv1.VirtualMachinePrintableStatus(""), virtv1.RunStrategyAlways, "", ""),
+			Entry("pause a running VM", vm.ActionPause, virtv1.VirtualMachineStatusRunning, virtv1.RunStrategyAlways, "", ""),
+			Entry("pause a paused VM", vm.ActionPause, virtv1.VirtualMachineStatusPaused, virtv1.RunStrategyAlways, "already paused", ""),
+			Entry("pause a stopped VM", vm.ActionPause, virtv1.VirtualMachineStatusStopped, virtv1.RunStrategyHalted, "", "cannot pause: VM test-vm is Stopped"),
+			Entry("pause a VM without status", vm.ActionPause, virtv1.VirtualMachinePrintableStatus(""), virtv1.RunStrategyManual, "", "cannot pause: VM test-vm is Unknown"),
+			Entry("unpause a paused VM", vm.ActionUnpause, virtv1.VirtualMachineStatusPaused, virtv1.RunStrategyAlways, "", ""),
+			Entry("unpause a running VM", vm.ActionUnpause, virtv1.VirtualMachineStatusRunning, virtv1.RunStrategyAlways, "not paused", ""),
+			Entry("unpause a stopped VM", vm.ActionUnpause, virtv1.VirtualMachineStatusStopped, virtv1.RunStrategyHalted, "", "cannot unpause: VM test-vm is Stopped"),
+			Entry("unpause a VM without status", vm.ActionUnpause, virtv1.VirtualMachinePrintableStatus(""), virtv1.RunStrategyManual, "", "cannot unpause: VM test-vm is Unknown"),
+		)
+	})
+
+	Describe("SnapshotConsistency", func() {
+		It("should report a stopped virtual machine as offline", func() {
+			Expect(vm.SnapshotConsistency(nil)).To(Equal("offline"))
+		})
+
+		It("should report a frozen filesystem with a connected guest agent", func() {
+			vmi := &virtv1.VirtualMachineInstance{}
+			vmi.Status.Conditions = []virtv1.VirtualMachineInstanceCondition{{
+				Type:   virtv1.VirtualMachineInstanceAgentConnected,
+				Status: "True",
+			}}
+			Expect(vm.SnapshotConsistency(vmi)).To(Equal("filesystem frozen by the guest agent"))
+		})
+
+		It("should report a crash consistent snapshot without a guest agent", func() {
+			Expect(vm.SnapshotConsistency(&virtv1.VirtualMachineInstance{})).To(Equal("crash consistent"))
+		})
+	})
+
+	Describe("ExportLinkSummary", func() {
+		link := &exportv1beta1.VirtualMachineExportLink{
+			Cert: "-----BEGIN CERTIFICATE-----",
+			Volumes: []exportv1beta1.VirtualMachineExportVolume{{
+				Name:    "rootdisk",
+				Formats: []exportv1beta1.VirtualMachineExportVolumeFormat{{Format: exportv1beta1.KubeVirtRaw, Url: "https://export/rootdisk.img"}},
+			}},
+		}
+
+		It("should leave out the certificate by default", func() {
+			summary := vm.ExportLinkSummary(link, false)
+
+			Expect(summary["cert"]).To(ContainSubstring("include_cert"))
+			Expect(summary["volumes"]).To(HaveLen(1))
+		})
+
+		It("should return the certificate when requested", func() {
+			Expect(vm.ExportLinkSummary(link, true)["cert"]).To(Equal(link.Cert))
+		})
+
+		It("should point at the system roots without a certificate", func() {
+			summary := vm.ExportLinkSummary(&exportv1beta1.VirtualMachineExportLink{}, true)
+
+			Expect(summary["cert"]).To(ContainSubstring("system roots"))
+		})
+	})
+
+	Describe("ExportRootCAs", func() {
+		It("should use the system roots without a certificate", func() {
+			roots, err := vm.ExportRootCAs("")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roots).To(BeNil())
+		})
+
+		It("should reject an invalid certificate", func() {
+			_, err := vm.ExportRootCAs("not a certificate")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("MigrationState", func() {
+		It("should prefer the state recorded on the running instance for the same migration", func() {
+			vmi := &virtv1.VirtualMachineInstance{}
+			vmi.Status.MigrationState = &virtv1.VirtualMachineInstanceMigrationState{MigrationUID: "current", TargetNode: "node-b"}
+			migration := &virtv1.VirtualMachineInstanceMigration{}
+			migration.UID = "current"
+			migration.Status.MigrationState = &virtv1.VirtualMachineInstanceMigrationState{TargetNode: "node-c"}
+
+			Expect(vm.MigrationState(vmi, migration).TargetNode).To(Equal("node-b"))
+		})
+
+		It("should ignore the state of a previous migration recorded on the instance", func() {
+			vmi := &virtv1.VirtualMachineInstance{}
+			vmi.Status.MigrationState = &virtv1.VirtualMachineInstanceMigrationState{MigrationUID: "previous", Completed: true}
+			migration := &virtv1.VirtualMachineInstanceMigration{}
+			migration.UID = "current"
+
+			Expect(vm.MigrationState(vmi, migration)).To(BeNil())
+		})
+
+		It("should use the state of the instance without a migration object", func() {
+			vmi := &virtv1.VirtualMachineInstance{}
+			vmi.Status.MigrationState = &virtv1.VirtualMachineInstanceMigrationState{MigrationUID: "deleted"}
+
+			Expect(vm.MigrationState(vmi, nil)).To(Equal(vmi.Status.MigrationState))
+		})
+
+		It("should fall back to the migration once the instance is gone", func() {
+			migration := &virtv1.VirtualMachineInstanceMigration{}
+			migration.Status.MigrationState = &virtv1.VirtualMachineInstanceMigrationState{Completed: true, TargetNode: "node-b"}
+
+			state := vm.MigrationState(nil, migration)
+
+			Expect(state).NotTo(BeNil())
+			Expect(state.Completed).To(BeTrue())
+		})
+
+		It("should report no state without an instance or migration", func() {
+			Expect(vm.MigrationState(nil, nil)).To(BeNil())
+		})
+	})
+
+	Describe("ForceStopNoop", func() {
+		DescribeTable("decides whether a VM without an instance needs stopping",
+			func(runStrategy virtv1.VirtualMachineRunStrategy, noop bool) {
+				message := vm.ForceStopNoop("test-vm", runStrategy)
+				if noop {
+					Expect(message).To(Equal("VM test-vm is already stopped"))
+				} else {
+					Expect(message).To(BeEmpty())
+				}
+			},
+			Entry("Halted", virtv1.RunStrategyHalted, true),
+			Entry("Manual", virtv1.RunStrategyManual, true),
+			Entry("Always", virtv1.RunStrategyAlways, false),
+			Entry("RerunOnFailure", virtv1.RunStrategyRerunOnFailure, false),
+		)
+	})
+
+	Describe("RestoreConditionMessage", func() {
+		restoreWith := func(conditions ...snapshotv1beta1.Condition) *snapshotv1beta1.VirtualMachineRestore {
+			return &snapshotv1beta1.VirtualMachineRestore{
+				Status: &snapshotv1beta1.VirtualMachineRestoreStatus{Conditions: conditions},
+			}
+		}
+
+		It("should report a true Failure condition as terminal", func() {
+			restore := restoreWith(
+				snapshotv1beta1.Condition{Type: snapshotv1beta1.ConditionReady, Status: corev1.ConditionFalse, Message: "Not ready"},
+				snapshotv1beta1.Condition{Type: snapshotv1beta1.ConditionFailure, Status: corev1.ConditionTrue, Message: "volume restore failed"},
+			)
+
+			Expect(vm.RestoreFailed(restore)).To(BeTrue())
+			Expect(vm.RestoreConditionMessage(restore)).To(Equal(": volume restore failed"))
+		})
+
+		It("should explain a restore that is still waiting", func() {
+			restore := restoreWith(
+				snapshotv1beta1.Condition{Type: snapshotv1beta1.ConditionProgressing, Status: corev1.ConditionFalse, Message: "Waiting for target VM to be powered off"},
+				snapshotv1beta1.Condition{Type: snapshotv1beta1.ConditionFailure, Status: corev1.ConditionFalse},
+			)
+
+			Expect(vm.RestoreFailed(restore)).To(BeFalse())
+			Expect(vm.RestoreConditionMessage(restore)).To(Equal(": Waiting for target VM to be powered off"))
+		})
+
+		It("should report nothing without a status", func() {
+			restore := &snapshotv1beta1.VirtualMachineRestore{}
+
+			Expect(vm.RestoreFailed(restore)).To(BeFalse())
+			Expect(vm.RestoreConditionMessage(restore)).To(BeEmpty())
+		})
+	})
+
+	Describe("CaptureText", func() {
+		It("should apply the limit to the text after stripping escape sequences", func() {
+			output, truncated := vm.CaptureText(5, "\x1b[1;32mlo", "gin\x1b[0m")
+
+			Expect(output).To(Equal("login"))
+			Expect(truncated).To(BeFalse())
+		})
+
+		It("should cut the stripped text to the limit", func() {
+			output, truncated := vm.CaptureText(5, "\x1b[1mlogin: \x1b[0m", "root\r\n")
+
+			Expect(output).To(Equal("login"))
+			Expect(truncated).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,254 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lyarwood/kubevirt-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	virtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+const (
+	hotplugVolumesGate = "HotplugVolumes"
+
+	// hotplugPollInterval and hotplugTimeout bound how long the volume tools
+	// wait for the hotplug to be reflected in the VMI volume status
+	hotplugPollInterval = time.Second
+	hotplugTimeout      = 30 * time.Second
+)
+
+func AddVolume(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	volumeName, err := request.RequireString("volume_name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("volume_name parameter required: %w", err))
+	}
+	diskName := request.GetString("disk_name", volumeName)
+	persist := request.GetBool("persist", false)
+
+	volumeSource, err := hotplugVolumeSource(request.GetString("source", "pvc"), volumeName)
+	if err != nil {
+		return newToolResultErr(err)
+	}
+	disk, err := hotplugDisk(request.GetString("disk_type", "disk"), request.GetString("bus", string(virtv1.DiskBusSCSI)))
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	if err := requireFeatureGate(ctx, virtClient, hotplugVolumesGate); err != nil {
+		return newToolResultErr(err)
+	}
+
+	options := &virtv1.AddVolumeOptions{
+		Name:         diskName,
+		Disk:         disk,
+		VolumeSource: volumeSource,
+	}
+	if persist {
+		err = virtClient.VirtualMachine(namespace).AddVolume(ctx, name, options)
+	} else {
+		if _, err := getRunningVMI(ctx, virtClient, namespace, name); err != nil {
+			return newToolResultErr(fmt.Errorf("%w, use persist to add the volume to the virtual machine spec", err))
+		}
+		err = virtClient.VirtualMachineInstance(namespace).AddVolume(ctx, name, options)
+	}
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	result := map[string]interface{}{
+		"vm":        name,
+		"namespace": namespace,
+		"volume":    diskName,
+		"persisted": persist,
+	}
+	status, running, err := waitForVolumeStatus(ctx, virtClient, namespace, name, diskName, func(status *virtv1.VolumeStatus) bool {
+		return status != nil && status.Phase == virtv1.VolumeReady
+	})
+	switch {
+	case err != nil:
+		result["volumeStatus"] = fmt.Sprintf("unable to read the volume status: %v", err)
+	case !running:
+		result["volumeStatus"] = "the virtual machine is not running, the volume is attached when it starts"
+	case status == nil:
+		result["volumeStatus"] = "not yet reported by the virtual machine instance"
+	default:
+		result["volumeStatus"] = volumeStatusSummary(status)
+	}
+	return newToolResultJSON(result)
+}
+
+func RemoveVolume(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	virtClient, err := client.GetKubevirtClient()
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("namespace parameter required: %w", err))
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("name parameter required: %w", err))
+	}
+	volumeName, err := request.RequireString("volume_name")
+	if err != nil {
+		return newToolResultErr(fmt.Errorf("volume_name parameter required: %w", err))
+	}
+	persist := request.GetBool("persist", false)
+
+	if err := requireFeatureGate(ctx, virtClient, hotplugVolumesGate); err != nil {
+		return newToolResultErr(err)
+	}
+
+	options := &virtv1.RemoveVolumeOptions{
+		Name: volumeName,
+	}
+	if persist {
+		err = virtClient.VirtualMachine(namespace).RemoveVolume(ctx, name, options)
+	} else {
+		if _, err := getRunningVMI(ctx, virtClient, namespace, name); err != nil {
+			return newToolResultErr(fmt.Errorf("%w, use persist to remove the volume from the virtual machine spec", err))
+		}
+		err = virtClient.VirtualMachineInstance(namespace).RemoveVolume(ctx, name, options)
+	}
+	if err != nil {
+		return newToolResultErr(err)
+	}
+
+	result := map[string]interface{}{
+		"vm":        name,
+		"namespace": namespace,
+		"volume":    volumeName,
+		"persisted": persist,
+	}
+	status, _, err := waitForVolumeStatus(ctx, virtClient, namespace, name, volumeName, func(status *virtv1.VolumeStatus) bool {
+		return status == nil
+	})
+	switch {
+	case err != nil:
+		result["volumeStatus"] = fmt.Sprintf("unable to read the volume status: %v", err)
+	case status == nil:
+		result["volumeStatus"] = "removed"
+	default:
+		result["volumeStatus"] = volumeStatusSummary(status)
+	}
+	return newToolResultJSON(result)
+}
+
+func hotplugVolumeSource(source, volumeName string) (*virtv1.HotplugVolumeSource, error) {
+	switch source {
+	case "pvc":
+		return &virtv1.HotplugVolumeSource{
+			PersistentVolumeClaim: &virtv1.PersistentVolumeClaimVolumeSource{
+				PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{ClaimName: volumeName},
+				Hotpluggable:                      true,
+			},
+		}, nil
+	case "datavolume":
+		return &virtv1.HotplugVolumeSource{
+			DataVolume: &virtv1.DataVolumeSource{
+				Name:         volumeName,
+				Hotpluggable: true,
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported source %q, expected pvc or datavolume", source)
+}
+
+func hotplugDisk(diskType, bus string) (*virtv1.Disk, error) {
+	diskBus := virtv1.DiskBus(bus)
+	if diskBus != virtv1.DiskBusSCSI && diskBus != virtv1.DiskBusVirtio {
+		return nil, fmt.Errorf("unsupported bus %q, hotplugged volumes support scsi or virtio", bus)
+	}
+
+	switch diskType {
+	case "disk":
+		return &virtv1.Disk{
+			DiskDevice: virtv1.DiskDevice{
+				Disk: &virtv1.DiskTarget{Bus: diskBus},
+			},
+		}, nil
+	case "lun":
+		if diskBus != virtv1.DiskBusSCSI {
+			return nil, fmt.Errorf("lun disks must use the scsi bus")
+		}
+		return &virtv1.Disk{
+			DiskDevice: virtv1.DiskDevice{
+				LUN: &virtv1.LunTarget{Bus: diskBus},
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported disk_type %q, expected disk or lun", diskType)
+}
+
+// waitForVolumeStatus polls the VMI volume status of a volume until done
+// reports true or the hotplug timeout expires, returning the last status seen
+// and whether the virtual machine is running. Persisted changes to a stopped
+// virtual machine have no VMI to report on and return straight away.
+func waitForVolumeStatus(ctx context.Context, virtClient kubecli.KubevirtClient, namespace, name, volumeName string, done func(*virtv1.VolumeStatus) bool) (*virtv1.VolumeStatus, bool, error) {
+	var status *virtv1.VolumeStatus
+	running := true
+	err := wait.PollUntilContextTimeout(ctx, hotplugPollInterval, hotplugTimeout, true, func(ctx context.Context) (bool, error) {
+		vmi, err := virtClient.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			status, running = nil, false
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		status = findVolumeStatus(vmi, volumeName)
+		return done(status), nil
+	})
+	if err != nil && !wait.Interrupted(err) {
+		return nil, false, err
+	}
+	return status, running, nil
+}
+
+func findVolumeStatus(vmi *virtv1.VirtualMachineInstance, volumeName string) *virtv1.VolumeStatus {
+	for i := range vmi.Status.VolumeStatus {
+		if vmi.Status.VolumeStatus[i].Name == volumeName {
+			return &vmi.Status.VolumeStatus[i]
+		}
+	}
+	return nil
+}
+
+func volumeStatusSummary(status *virtv1.VolumeStatus) map[string]interface{} {
+	summary := map[string]interface{}{
+		"phase":  status.Phase,
+		"target": status.Target,
+	}
+	if status.Reason != "" {
+		summary["reason"] = status.Reason
+	}
+	if status.Message != "" {
+		summary["message"] = status.Message
+	}
+	if status.HotplugVolume != nil && status.HotplugVolume.AttachPodName != "" {
+		summary["attachPod"] = status.HotplugVolume.AttachPodName
+	}
+	return summary
+}